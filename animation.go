@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "fmt"
+
+// AnimationMode tells what an animation does after its last frame.
+type AnimationMode uint8
+
+const (
+	// AnimationLoop starts over from the first frame.
+	AnimationLoop AnimationMode = iota
+	// AnimationHold stays on the last frame.
+	AnimationHold
+	// AnimationOneShot ends the animation, the sprite playing it is removed.
+	AnimationOneShot
+)
+
+// ParseAnimationMode parses the mode name used in asset packs: "loop", "hold" or "once".
+func ParseAnimationMode(name string) (AnimationMode, error) {
+	switch name {
+	case "loop":
+		return AnimationLoop, nil
+	case "hold":
+		return AnimationHold, nil
+	case "once":
+		return AnimationOneShot, nil
+	}
+	return 0, fmt.Errorf("unknown animation mode %q, expected loop, hold or once", name)
+}
+
+// AnimationFrame is one frame of an animation.
+// Row is the frame row of the sprite texture and Duration how long it is shown, in seconds.
+type AnimationFrame struct {
+	Row      int
+	Duration float64
+}
+
+// Animation is a sequence of frames played in order.
+type Animation struct {
+	Frames []AnimationFrame
+	Mode   AnimationMode
+}
+
+const (
+	lanternFlickerShort = 0.08
+	lanternFlickerLong  = 0.35
+	walkFrameDuration   = 0.12
+	poofFrameDuration   = 0.07
+)
+
+//nolint:gochecknoglobals // animations shared by the sprites playing them
+var (
+	// AnimLanternFlicker dims the light cone of the lanterns at an uneven pace.
+	AnimLanternFlicker = Animation{
+		Frames: []AnimationFrame{
+			{Row: 0, Duration: lanternFlickerLong},
+			{Row: 2, Duration: lanternFlickerShort},
+			{Row: 0, Duration: lanternFlickerLong * 2},
+			{Row: 1, Duration: lanternFlickerShort},
+			{Row: 3, Duration: lanternFlickerShort},
+			{Row: 2, Duration: lanternFlickerShort * 2},
+			{Row: 0, Duration: lanternFlickerLong},
+			{Row: 1, Duration: lanternFlickerShort},
+		},
+		Mode: AnimationLoop,
+	}
+
+	// AnimIdle is a character standing still.
+	AnimIdle = Animation{
+		Frames: []AnimationFrame{{Row: 0, Duration: 1}},
+		Mode:   AnimationLoop,
+	}
+
+	// AnimWalk is the walk cycle of a character.
+	AnimWalk = Animation{
+		Frames: []AnimationFrame{
+			{Row: 1, Duration: walkFrameDuration},
+			{Row: 2, Duration: walkFrameDuration},
+			{Row: 3, Duration: walkFrameDuration},
+			{Row: 4, Duration: walkFrameDuration},
+		},
+		Mode: AnimationLoop,
+	}
+
+	// AnimPoof is the smoke puff shown when a mark appears in a room.
+	AnimPoof = Animation{
+		Frames: []AnimationFrame{
+			{Row: 0, Duration: poofFrameDuration},
+			{Row: 1, Duration: poofFrameDuration},
+			{Row: 2, Duration: poofFrameDuration},
+			{Row: 3, Duration: poofFrameDuration},
+			{Row: 4, Duration: poofFrameDuration},
+			{Row: 5, Duration: poofFrameDuration},
+		},
+		Mode: AnimationOneShot,
+	}
+)
+
+// AnimationState is the playback position of an animation.
+// The zero value plays nothing and shows row 0.
+type AnimationState struct {
+	anim    *Animation
+	frame   int
+	elapsed float64
+	done    bool
+}
+
+// NewAnimationState starts the animation, already advanced by offset seconds.
+// Sprites sharing an animation use different offsets so they do not play in sync.
+func NewAnimationState(anim *Animation, offset float64) AnimationState {
+	s := AnimationState{anim: anim, frame: 0, elapsed: 0, done: false}
+	s.Advance(offset)
+	return s
+}
+
+// Play switches to the animation from its first frame, it keeps playing if it is already the current one.
+func (s *AnimationState) Play(anim *Animation) {
+	if s.anim == anim {
+		return
+	}
+	*s = AnimationState{anim: anim, frame: 0, elapsed: 0, done: false}
+}
+
+// Advance moves the animation forward by dt seconds.
+func (s *AnimationState) Advance(dt float64) {
+	if s.anim == nil || s.done || len(s.anim.Frames) == 0 {
+		return
+	}
+
+	s.elapsed += dt
+	for {
+		duration := s.anim.Frames[s.frame].Duration
+		if duration <= 0 || s.elapsed < duration {
+			return
+		}
+		s.elapsed -= duration
+
+		if s.frame < len(s.anim.Frames)-1 {
+			s.frame++
+			continue
+		}
+
+		switch s.anim.Mode {
+		case AnimationLoop:
+			s.frame = 0
+		case AnimationHold:
+			s.elapsed = 0
+			return
+		case AnimationOneShot:
+			s.done = true
+			return
+		}
+	}
+}
+
+// Row returns the texture frame row to draw.
+func (s AnimationState) Row() int {
+	if s.anim == nil || len(s.anim.Frames) == 0 {
+		return 0
+	}
+	return s.anim.Frames[s.frame].Row
+}
+
+// Done returns true once a one shot animation played its last frame.
+func (s AnimationState) Done() bool {
+	return s.done
+}
+
+// SpriteAnimator advances the animation of every world sprite and removes the finished one shot sprites.
+type SpriteAnimator struct{}
+
+func (a *SpriteAnimator) Update(g *Game) {
+	filtered := g.sprites[:0]
+	for _, s := range g.sprites {
+		s.Anim.Advance(DeltaTime)
+		if s.Anim.Done() {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	g.sprites = filtered
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func testAnimation(mode AnimationMode) *Animation {
+	return &Animation{
+		Frames: []AnimationFrame{{Row: 0, Duration: 1}, {Row: 1, Duration: 0.5}, {Row: 2, Duration: 1}},
+		Mode:   mode,
+	}
+}
+
+func TestAnimationState_Advance(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     AnimationMode
+		elapsed  float64
+		wantRow  int
+		wantDone bool
+	}{
+		{name: "First frame", mode: AnimationLoop, elapsed: 0.9, wantRow: 0, wantDone: false},
+		{name: "Second frame", mode: AnimationLoop, elapsed: 1.2, wantRow: 1, wantDone: false},
+		{name: "Several frames at once", mode: AnimationLoop, elapsed: 1.6, wantRow: 2, wantDone: false},
+		{name: "Loop", mode: AnimationLoop, elapsed: 2.6, wantRow: 0, wantDone: false},
+		{name: "Hold", mode: AnimationHold, elapsed: 10, wantRow: 2, wantDone: false},
+		{name: "One shot playing", mode: AnimationOneShot, elapsed: 2.4, wantRow: 2, wantDone: false},
+		{name: "One shot done", mode: AnimationOneShot, elapsed: 2.6, wantRow: 2, wantDone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAnimationState(testAnimation(tt.mode), 0)
+			s.Advance(tt.elapsed)
+
+			if got := s.Row(); got != tt.wantRow {
+				t.Errorf("row = %d, want %d", got, tt.wantRow)
+			}
+			if got := s.Done(); got != tt.wantDone {
+				t.Errorf("done = %v, want %v", got, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestAnimationState_Offset(t *testing.T) {
+	anim := testAnimation(AnimationLoop)
+	a := NewAnimationState(anim, 0)
+	b := NewAnimationState(anim, 1.2)
+
+	if a.Row() == b.Row() {
+		t.Errorf("offset animations show the same row %d", a.Row())
+	}
+}
+
+func TestAnimationState_Play(t *testing.T) {
+	walk := testAnimation(AnimationLoop)
+	idle := &Animation{Frames: []AnimationFrame{{Row: 5, Duration: 1}}, Mode: AnimationLoop}
+
+	s := NewAnimationState(walk, 1.2)
+
+	// playing the current animation keeps its position
+	s.Play(walk)
+	if s.Row() != 1 {
+		t.Errorf("row after replaying = %d, want 1", s.Row())
+	}
+
+	s.Play(idle)
+	if s.Row() != 5 {
+		t.Errorf("row after switching = %d, want 5", s.Row())
+	}
+
+	s.Play(walk)
+	if s.Row() != 0 {
+		t.Errorf("row after switching back = %d, want the first frame", s.Row())
+	}
+}
+
+func TestAnimationState_ZeroValue(t *testing.T) {
+	var s AnimationState
+	s.Advance(1)
+	if s.Row() != 0 || s.Done() {
+		t.Errorf("zero state: row %d, done %v", s.Row(), s.Done())
+	}
+}
+
+func TestSpriteAnimator_RemovesFinishedSprites(t *testing.T) {
+	mark := &Sprite{Position: Vec2{X: 3.5, Y: 3.5}, TextureID: PlayerXSymbol, Scale: 1}
+	poof := spriteTypes["poof"].NewSprite(mark.Position)
+	g := &Game{sprites: []*Sprite{mark, poof}}
+
+	// whole ticks, just short of the animation length
+	ticks := int(float64(len(AnimPoof.Frames)) * poofFrameDuration * TPS)
+
+	a := &SpriteAnimator{}
+	for range ticks {
+		a.Update(g)
+	}
+	if len(g.sprites) != 2 {
+		t.Fatalf("poof removed before its last frame: %d sprites", len(g.sprites))
+	}
+
+	// a few more ticks for the float rounding of the frame durations
+	for range 3 {
+		a.Update(g)
+	}
+	if len(g.sprites) != 1 || g.sprites[0] != mark {
+		t.Errorf("got %d sprites, want only the mark", len(g.sprites))
+	}
+}
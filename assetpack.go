@@ -0,0 +1,288 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+)
+
+const (
+	// AssetPackManifestName is the manifest file at the root of an asset pack.
+	AssetPackManifestName = "pack.json"
+
+	// firstSpriteTextureID is the first texture ID reserved for sprites, see TextureID.
+	firstSpriteTextureID = 128
+)
+
+// AssetPack holds the resources of a pack, decoded and ready to override or extend the embedded assets.
+// Textures only have their Pixels set, SpriteTypes are the new or replaced decoration types by name
+// and Font is the replacement font file, nil to keep the embedded one.
+type AssetPack struct {
+	Name        string
+	Textures    TextureMap
+	SpriteTypes map[string]SpriteType
+	Font        []byte
+}
+
+// assetPackFile is the json manifest of an asset pack. Files are relative to the manifest.
+// walls add or replace the texture of a wall TileID (1-127), maps can then use the tile.
+// sprites add or replace a decoration type, spawned with the console "spawn" command.
+// textures replace any other texture by ID, like the player characters or the marks.
+type assetPackFile struct {
+	Name     string             `json:"name"`
+	Font     string             `json:"font,omitempty"`
+	Walls    []assetPackWall    `json:"walls,omitempty"`
+	Sprites  []assetPackSprite  `json:"sprites,omitempty"`
+	Textures []assetPackTexture `json:"textures,omitempty"`
+}
+
+type assetPackWall struct {
+	Tile TileID `json:"tile"`
+	File string `json:"file"`
+}
+
+type assetPackTexture struct {
+	ID     TextureID `json:"id"`
+	File   string    `json:"file"`
+	Frames int       `json:"frames,omitempty"`
+}
+
+// assetPackSprite is a decoration type. A scale of 0 means 1, z moves the sprite up (positive) or down.
+type assetPackSprite struct {
+	Name      string              `json:"name"`
+	File      string              `json:"file"`
+	Frames    int                 `json:"frames,omitempty"`
+	Scale     float64             `json:"scale,omitempty"`
+	Z         float64             `json:"z,omitempty"`
+	Animation *assetPackAnimation `json:"animation,omitempty"`
+}
+
+// assetPackAnimation is the animation played by a sprite type, mode is "loop", "hold" or "once".
+type assetPackAnimation struct {
+	Mode   string                    `json:"mode"`
+	Frames []assetPackAnimationFrame `json:"frames"`
+}
+
+type assetPackAnimationFrame struct {
+	Row      int     `json:"row"`
+	Duration float64 `json:"duration"`
+}
+
+// errAssetPackUnreadable is returned when the asset pack or its manifest cannot be read, no entry is loaded then.
+var errAssetPackUnreadable = errors.New("cannot read asset pack")
+
+// LoadAssetPack loads the asset pack in the directory or zip file at path.
+// A zip may hold the manifest at its root or in a single top level directory.
+// Entries that cannot be loaded are skipped: the returned pack holds every valid entry
+// and the error lists the others, so callers can report it and still use the pack.
+// When the pack or its manifest cannot be read, the pack is empty and the error wraps errAssetPackUnreadable.
+func LoadAssetPack(path string) (AssetPack, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return AssetPack{}, fmt.Errorf("%w: %w", errAssetPackUnreadable, err)
+	}
+
+	if info.IsDir() {
+		return loadAssetPackFS(os.DirFS(path))
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return AssetPack{}, fmt.Errorf("%w: open %q: %w", errAssetPackUnreadable, path, err)
+	}
+	defer r.Close()
+
+	// everything is decoded while loading, the zip can be closed afterwards
+	return loadAssetPackFS(r)
+}
+
+// loadAssetPackFS loads an asset pack from its files, see LoadAssetPack.
+func loadAssetPackFS(fsys fs.FS) (AssetPack, error) {
+	fsys = assetPackRoot(fsys)
+
+	data, err := fs.ReadFile(fsys, AssetPackManifestName)
+	if err != nil {
+		return AssetPack{}, fmt.Errorf("%w: read manifest: %w", errAssetPackUnreadable, err)
+	}
+
+	var f assetPackFile
+	if errU := json.Unmarshal(data, &f); errU != nil {
+		return AssetPack{}, fmt.Errorf("%w: decode manifest: %w", errAssetPackUnreadable, errU)
+	}
+
+	pack := AssetPack{
+		Name:        f.Name,
+		Textures:    make(TextureMap),
+		SpriteTypes: make(map[string]SpriteType),
+		Font:        nil,
+	}
+	var errs []error
+
+	if f.Font != "" {
+		font, errF := fs.ReadFile(fsys, f.Font)
+		if errF != nil {
+			errs = append(errs, fmt.Errorf("font: %w", errF))
+		} else {
+			pack.Font = font
+		}
+	}
+
+	for _, w := range f.Walls {
+		if w.Tile == TileEmpty || w.Tile >= firstSpriteTextureID {
+			errs = append(errs, fmt.Errorf("wall %q: tile %d is not between 1 and %d", w.File, w.Tile, firstSpriteTextureID-1))
+			continue
+		}
+		if errT := pack.addTexture(fsys, TextureID(w.Tile), w.File, 1); errT != nil {
+			errs = append(errs, fmt.Errorf("wall %d: %w", w.Tile, errT))
+		}
+	}
+
+	for _, t := range f.Textures {
+		if t.ID == 0 {
+			errs = append(errs, fmt.Errorf("texture %q: missing id", t.File))
+			continue
+		}
+		if errT := pack.addTexture(fsys, t.ID, t.File, t.Frames); errT != nil {
+			errs = append(errs, fmt.Errorf("texture %d: %w", t.ID, errT))
+		}
+	}
+
+	for _, s := range f.Sprites {
+		if errS := pack.addSpriteType(fsys, s); errS != nil {
+			errs = append(errs, fmt.Errorf("sprite %q: %w", s.Name, errS))
+		}
+	}
+
+	return pack, errors.Join(errs...)
+}
+
+// assetPackRoot returns the directory holding the manifest: the root, or its only directory
+// for zips created by compressing the pack folder itself.
+func assetPackRoot(fsys fs.FS) fs.FS {
+	if _, err := fs.Stat(fsys, AssetPackManifestName); err == nil {
+		return fsys
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return fsys
+	}
+
+	sub, err := fs.Sub(fsys, entries[0].Name())
+	if err != nil {
+		return fsys
+	}
+	return sub
+}
+
+// addTexture decodes the image file and adds it to the pack textures.
+func (p *AssetPack) addTexture(fsys fs.FS, id TextureID, file string, frames int) error {
+	pixels, err := decodeTexturePixels(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	texture, err := newTexture(pixels, frames)
+	if err != nil {
+		return fmt.Errorf("%q: %w", file, err)
+	}
+	p.Textures[id] = texture
+	return nil
+}
+
+// addSpriteType adds a decoration type with its texture.
+// a type replacing a built-in one reuses its texture ID, a new one takes the first free sprite texture ID.
+func (p *AssetPack) addSpriteType(fsys fs.FS, s assetPackSprite) error {
+	if s.Name == "" {
+		return errors.New("missing name")
+	}
+	if _, ok := p.SpriteTypes[s.Name]; ok {
+		return errors.New("defined twice")
+	}
+
+	var anim *Animation
+	if s.Animation != nil {
+		a, err := s.Animation.toAnimation()
+		if err != nil {
+			return fmt.Errorf("animation: %w", err)
+		}
+		anim = &a
+	}
+
+	id, ok := p.spriteTextureID(s.Name)
+	if !ok {
+		return errors.New("no free sprite texture ID")
+	}
+	if err := p.addTexture(fsys, id, s.File, s.Frames); err != nil {
+		return err
+	}
+
+	scale := s.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	p.SpriteTypes[s.Name] = SpriteType{TextureID: id, Scale: scale, Z: s.Z, Animation: anim}
+	return nil
+}
+
+// spriteTextureID returns the texture ID of a sprite type: the one of the built-in type with that name,
+// or the first sprite texture ID used neither by the embedded textures nor by the pack.
+func (p *AssetPack) spriteTextureID(name string) (TextureID, bool) {
+	if t, ok := spriteTypes[name]; ok {
+		return t.TextureID, true
+	}
+
+	for id := firstSpriteTextureID; id <= int(^TextureID(0)); id++ {
+		_, embedded := imageManifest[TextureID(id)]
+		_, used := p.Textures[TextureID(id)]
+		if !embedded && !used {
+			return TextureID(id), true
+		}
+	}
+	return 0, false
+}
+
+// toAnimation validates the animation of the manifest.
+func (a assetPackAnimation) toAnimation() (Animation, error) {
+	mode, err := ParseAnimationMode(a.Mode)
+	if err != nil {
+		return Animation{}, err
+	}
+	if len(a.Frames) == 0 {
+		return Animation{}, errors.New("no frames")
+	}
+
+	frames := make([]AnimationFrame, 0, len(a.Frames))
+	for i, f := range a.Frames {
+		if f.Row < 0 || f.Duration <= 0 {
+			return Animation{}, fmt.Errorf("frame %d: invalid row %d or duration %.2f", i, f.Row, f.Duration)
+		}
+		frames = append(frames, AnimationFrame{Row: f.Row, Duration: f.Duration})
+	}
+	return Animation{Frames: frames, Mode: mode}, nil
+}
+
+// Apply returns the textures and sprite types overridden and extended by the pack.
+// The inputs are not modified.
+func (p AssetPack) Apply(textures TextureMap, types map[string]SpriteType) (TextureMap, map[string]SpriteType) {
+	outTextures := maps.Clone(textures)
+	if outTextures == nil {
+		outTextures = make(TextureMap, len(p.Textures))
+	}
+	maps.Copy(outTextures, p.Textures)
+
+	outTypes := maps.Clone(types)
+	if outTypes == nil {
+		outTypes = make(map[string]SpriteType, len(p.SpriteTypes))
+	}
+	maps.Copy(outTypes, p.SpriteTypes)
+
+	return outTextures, outTypes
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// solidPNG returns a png of the given size filled with c.
+func solidPNG(t *testing.T, w, h int, c color.RGBA) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testPackFiles is a pack with a new wall tile, a replaced wall, a new and a replaced sprite type.
+func testPackFiles(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	red := color.RGBA{R: 255, A: 255}
+	return fstest.MapFS{
+		AssetPackManifestName: {Data: []byte(`{
+			"name": "test pack",
+			"walls": [{"tile": 1, "file": "walls/red.png"}, {"tile": 9, "file": "walls/moss.png"}],
+			"sprites": [
+				{"name": "barrel", "file": "barrel.png", "scale": 0.5, "z": -1,
+				 "animation": {"mode": "loop", "frames": [{"row": 0, "duration": 0.1}, {"row": 1, "duration": 0.1}]}},
+				{"name": "skull", "file": "skull.png"}
+			],
+			"textures": [{"id": 129, "file": "x-player.png", "frames": 8}]
+		}`)},
+		"walls/red.png":  {Data: solidPNG(t, 16, 16, red)},
+		"walls/moss.png": {Data: solidPNG(t, 128, 128, color.RGBA{G: 200, A: 255})},
+		"barrel.png":     {Data: solidPNG(t, 32, 64, color.RGBA{R: 120, G: 80, A: 255})},
+		"skull.png":      {Data: solidPNG(t, 64, 64, color.RGBA{R: 250, G: 250, B: 250, A: 255})},
+		"x-player.png":   {Data: solidPNG(t, 1024, 128, red)},
+	}
+}
+
+func TestLoadAssetPackFS(t *testing.T) {
+	pack, err := loadAssetPackFS(testPackFiles(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pack.Name != "test pack" {
+		t.Errorf("name = %q, want %q", pack.Name, "test pack")
+	}
+	if tex := pack.Textures[TextureID(9)]; tex.Size != 128 {
+		t.Errorf("new wall texture size = %d, want 128", tex.Size)
+	}
+	if tex := pack.Textures[PlayerXCharacter]; tex.Frames != 8 || tex.Size != 128 {
+		t.Errorf("character sheet: %d frames of %d, want 8 frames of 128", tex.Frames, tex.Size)
+	}
+
+	// a replaced sprite type keeps its texture ID, a new one gets a free sprite ID
+	if got := pack.SpriteTypes["skull"].TextureID; got != SkeletonSkull {
+		t.Errorf("skull texture = %d, want %d", got, SkeletonSkull)
+	}
+	barrel := pack.SpriteTypes["barrel"]
+	if _, embedded := imageManifest[barrel.TextureID]; embedded || barrel.TextureID < firstSpriteTextureID {
+		t.Errorf("barrel texture ID %d is not a free sprite ID", barrel.TextureID)
+	}
+	if tex := pack.Textures[barrel.TextureID]; tex.Size != 32 || tex.Rows != 2 {
+		t.Errorf("barrel texture: size %d rows %d, want size 32 rows 2", tex.Size, tex.Rows)
+	}
+	if barrel.Scale != 0.5 || barrel.Z != -1 || barrel.Animation == nil || len(barrel.Animation.Frames) != 2 {
+		t.Errorf("barrel type = %+v", barrel)
+	}
+	if pack.SpriteTypes["skull"].Scale != 1 {
+		t.Errorf("default scale = %v, want 1", pack.SpriteTypes["skull"].Scale)
+	}
+}
+
+func TestLoadAssetPackFS_InvalidEntries(t *testing.T) {
+	files := testPackFiles(t)
+	files[AssetPackManifestName] = &fstest.MapFile{Data: []byte(`{
+		"walls": [
+			{"tile": 1, "file": "walls/red.png"},
+			{"tile": 200, "file": "walls/red.png"},
+			{"tile": 2, "file": "missing.png"},
+			{"tile": 3, "file": "odd.png"}
+		],
+		"sprites": [{"name": "", "file": "barrel.png"}, {"name": "bad", "file": "barrel.png", "animation": {"mode": "bounce"}}],
+		"textures": [{"file": "skull.png"}],
+		"font": "missing.ttf"
+	}`)}
+	files["odd.png"] = &fstest.MapFile{Data: solidPNG(t, 48, 48, color.RGBA{A: 255})}
+
+	pack, err := loadAssetPackFS(files)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// every broken entry is reported, the valid one is kept
+	for _, want := range []string{"tile 200", "wall 2", "wall 3", "missing name", "bounce", "missing id", "font"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if len(pack.Textures) != 1 || pack.Textures[WallBrick].Pixels == nil {
+		t.Errorf("got %d textures, want only the valid wall", len(pack.Textures))
+	}
+	if len(pack.SpriteTypes) != 0 || pack.Font != nil {
+		t.Errorf("got %d sprite types and a font, want none", len(pack.SpriteTypes))
+	}
+}
+
+func TestLoadAssetPack_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, AssetPackManifestName), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	notZip := filepath.Join(t.TempDir(), "pack.zip")
+	if err := os.WriteFile(notZip, []byte("not a zip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "Missing", path: filepath.Join(dir, "missing")},
+		{name: "Malformed manifest", path: dir},
+		{name: "No manifest", path: t.TempDir()},
+		{name: "Not a zip", path: notZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack, err := LoadAssetPack(tt.path)
+			if !errors.Is(err, errAssetPackUnreadable) {
+				t.Fatalf("LoadAssetPack() = %v, want an unreadable pack", err)
+			}
+			if len(pack.Textures) != 0 || len(pack.SpriteTypes) != 0 {
+				t.Error("a pack that cannot be read must be empty")
+			}
+		})
+	}
+}
+
+func TestLoadAssetPack_ZipWithFolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pack.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zw := zip.NewWriter(f)
+	for name, file := range testPackFiles(t) {
+		w, errC := zw.Create("my-pack/" + name)
+		if errC != nil {
+			t.Fatal(errC)
+		}
+		if _, errW := w.Write(file.Data); errW != nil {
+			t.Fatal(errW)
+		}
+	}
+	if errZ := zw.Close(); errZ != nil {
+		t.Fatal(errZ)
+	}
+	if errF := f.Close(); errF != nil {
+		t.Fatal(errF)
+	}
+
+	pack, err := LoadAssetPack(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := pack.SpriteTypes["barrel"]; !ok {
+		t.Error("barrel sprite type not loaded from the zip")
+	}
+}
+
+func TestAssetPack_Apply(t *testing.T) {
+	pack, err := loadAssetPackFS(testPackFiles(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base, err := LoadTexturePixels()
+	if err != nil {
+		t.Fatalf("load textures: %v", err)
+	}
+	brick := base[WallBrick]
+
+	textures, types := pack.Apply(base, spriteTypes)
+	if textures[WallBrick].Size != 16 || textures[TextureID(9)].Pixels == nil {
+		t.Error("pack walls are not applied")
+	}
+	if textures[Chains].Pixels != base[Chains].Pixels {
+		t.Error("textures missing from the pack must keep the embedded ones")
+	}
+	if _, ok := types["barrel"]; !ok || types["light"] != spriteTypes["light"] {
+		t.Error("sprite types are not merged")
+	}
+
+	// the inputs are left untouched
+	if base[WallBrick].Pixels != brick.Pixels {
+		t.Error("Apply modified the embedded textures")
+	}
+	if _, ok := spriteTypes["barrel"]; ok {
+		t.Error("Apply modified the built-in sprite types")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"log/slog"
 
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
@@ -15,17 +16,25 @@ import (
 // NormalTextFace is the standard font face used for most text rendering.
 // BigTextFace is a larger font face used for titles and important messages.
 // Textures holds all loaded textures mapped by their TextureId.
+// SpriteTypes are the decoration types that can be spawned by name, the built-in ones and those of the asset pack.
+// PackName is the name of the loaded asset pack, empty without one.
 type Assets struct {
 	NormalTextFace *text.GoTextFace
 	BigTextFace    *text.GoTextFace
 	Textures       TextureMap
+	SpriteTypes    map[string]SpriteType
+	PackName       string
 }
 
 //go:embed assets/fonts/*.ttf
 var fontsFS embed.FS
 
 // loadAssets loads all game resources. After this returns, Assets should be treated as read only.
-func loadAssets() (*Assets, error) {
+// packPath is an asset pack directory or zip overriding the embedded resources, empty for none.
+// A pack that cannot be loaded, fully or partly, is reported and the embedded resources are used instead.
+func loadAssets(packPath string) (*Assets, error) {
+	pack := loadAssetPackOrWarn(packPath)
+
 	fontBytes, err := fontsFS.ReadFile("assets/fonts/PressStart2P-Regular.ttf")
 	if err != nil {
 		return nil, fmt.Errorf("load font: %w", err)
@@ -35,10 +44,18 @@ func loadAssets() (*Assets, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create font source: %w", err)
 	}
+	if pack.Font != nil {
+		packFont, errF := text.NewGoTextFaceSource(bytes.NewReader(pack.Font))
+		if errF != nil {
+			slog.Warn("asset pack font", "pack", packPath, "error", errF)
+		} else {
+			fontSource = packFont
+		}
+	}
 
-	textures, err := LoadTextures()
+	textures, types, err := loadPackTextures(pack)
 	if err != nil {
-		return nil, fmt.Errorf("load textures: %w", err)
+		return nil, err
 	}
 
 	return &Assets{
@@ -51,6 +68,37 @@ func loadAssets() (*Assets, error) {
 			Size:   BigFontSize,
 		},
 
-		Textures: textures,
+		Textures:    textures,
+		SpriteTypes: types,
+		PackName:    pack.Name,
 	}, nil
 }
+
+// loadPackTextures loads the embedded textures overridden by the pack ones, and the sprite types.
+func loadPackTextures(pack AssetPack) (TextureMap, map[string]SpriteType, error) {
+	pixels, err := LoadTexturePixels()
+	if err != nil {
+		return nil, nil, fmt.Errorf("load textures: %w", err)
+	}
+	pixels, types := pack.Apply(pixels, spriteTypes)
+
+	textures, err := UploadTextures(pixels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load textures: %w", err)
+	}
+	return textures, types, nil
+}
+
+// loadAssetPackOrWarn loads the asset pack at path, an empty path is no pack.
+// Errors are logged, the valid entries of a partly broken pack are still used.
+func loadAssetPackOrWarn(path string) AssetPack {
+	if path == "" {
+		return AssetPack{}
+	}
+
+	pack, err := LoadAssetPack(path)
+	if err != nil {
+		slog.Warn("asset pack", "pack", path, "error", err)
+	}
+	return pack
+}
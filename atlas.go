@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/draw"
+	"slices"
+)
+
+// TextureAtlas is every texture packed into a single image.
+// Rects is the region of each texture in Pixels.
+type TextureAtlas struct {
+	Pixels *image.RGBA
+	Rects  map[TextureID]image.Rectangle
+}
+
+// newTextureAtlas packs the texture pixels into an atlas no larger than maxSize on each side.
+func newTextureAtlas(textures TextureMap, maxSize int) (TextureAtlas, error) {
+	sizes := make(map[TextureID]image.Point, len(textures))
+	for id, t := range textures {
+		sizes[id] = t.Pixels.Rect.Size()
+	}
+
+	rects, size, err := packAtlas(sizes, maxSize)
+	if err != nil {
+		return TextureAtlas{}, err
+	}
+
+	pixels := image.NewRGBA(image.Rectangle{Min: image.Point{}, Max: size})
+	for id, r := range rects {
+		src := textures[id].Pixels
+		draw.Draw(pixels, r, src, src.Rect.Min, draw.Src)
+	}
+
+	return TextureAtlas{Pixels: pixels, Rects: rects}, nil
+}
+
+// packAtlas places the rectangles of the given sizes in shelves, the tallest first.
+// the atlas width is the smallest power of two fitting the widest image and about the square root of the total area.
+// it returns the region of every image and the atlas size.
+func packAtlas(sizes map[TextureID]image.Point, maxSize int) (map[TextureID]image.Rectangle, image.Point, error) {
+	ids := make([]TextureID, 0, len(sizes))
+	widest, area := 1, 0
+	for id, s := range sizes {
+		ids = append(ids, id)
+		widest = max(widest, s.X)
+		area += s.X * s.Y
+	}
+
+	// tallest first, then by id so the layout is the same on every run
+	slices.SortFunc(ids, func(a, b TextureID) int {
+		if c := cmp.Compare(sizes[b].Y, sizes[a].Y); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	width := 1
+	for width < widest || width*width < area {
+		width *= 2
+	}
+	if width > maxSize {
+		return nil, image.Point{}, fmt.Errorf("atlas width %d is larger than %d", width, maxSize)
+	}
+
+	rects := make(map[TextureID]image.Rectangle, len(ids))
+	x, shelfY, shelfH := 0, 0, 0
+	for _, id := range ids {
+		s := sizes[id]
+		if x+s.X > width {
+			x, shelfY, shelfH = 0, shelfY+shelfH, 0
+		}
+
+		rects[id] = image.Rect(x, shelfY, x+s.X, shelfY+s.Y)
+		x += s.X
+		shelfH = max(shelfH, s.Y)
+	}
+
+	height := shelfY + shelfH
+	if height > maxSize {
+		return nil, image.Point{}, fmt.Errorf("atlas height %d is larger than %d", height, maxSize)
+	}
+	return rects, image.Point{X: width, Y: height}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPackAtlas(t *testing.T) {
+	sizes := map[TextureID]image.Point{
+		WallBrick:        {X: 64, Y: 64},
+		WallBrickHole:    {X: 128, Y: 128},
+		PlayerXCharacter: {X: 512, Y: 320},
+		Light:            {X: 64, Y: 256},
+		Poof:             {X: 32, Y: 192},
+	}
+
+	rects, size, err := packAtlas(sizes, TextureAtlasMaxSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bounds := image.Rectangle{Min: image.Point{}, Max: size}
+	for id, r := range rects {
+		if r.Size() != sizes[id] {
+			t.Errorf("texture %d: size %v, want %v", id, r.Size(), sizes[id])
+		}
+		if !r.In(bounds) {
+			t.Errorf("texture %d: %v is outside of the atlas %v", id, r, bounds)
+		}
+		for other, o := range rects {
+			if other != id && r.Overlaps(o) {
+				t.Errorf("textures %d and %d overlap: %v %v", id, other, r, o)
+			}
+		}
+	}
+	if !isPowerOfTwo(size.X) {
+		t.Errorf("atlas width %d is not a power of two", size.X)
+	}
+}
+
+func TestPackAtlas_TooLarge(t *testing.T) {
+	sizes := map[TextureID]image.Point{
+		WallBrick:     {X: 256, Y: 256},
+		WallBrickHole: {X: 256, Y: 256},
+	}
+	if _, _, err := packAtlas(sizes, 128); err == nil {
+		t.Error("expected an error for a texture wider than the atlas")
+	}
+	if _, _, err := packAtlas(sizes, 256); err == nil {
+		t.Error("expected an error for textures taller than the atlas")
+	}
+}
+
+func TestNewTextureAtlas_CopiesPixels(t *testing.T) {
+	textures, err := LoadTexturePixels()
+	if err != nil {
+		t.Fatalf("load textures: %v", err)
+	}
+
+	atlas, err := newTextureAtlas(textures, TextureAtlasMaxSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for id, texture := range textures {
+		r := atlas.Rects[id]
+		b := texture.Pixels.Rect
+		for _, p := range []image.Point{{X: 0, Y: 0}, {X: b.Dx() / 2, Y: b.Dy() / 2}, {X: b.Dx() - 1, Y: b.Dy() - 1}} {
+			want := texture.Pixels.RGBAAt(p.X, p.Y)
+			got, _ := color.RGBAModel.Convert(atlas.Pixels.At(r.Min.X+p.X, r.Min.Y+p.Y)).(color.RGBA)
+			if got != want {
+				t.Errorf("texture %d pixel %v = %v, want %v", id, p, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+//go:embed assets/sounds/*
+var soundsFS embed.FS
+
+// SoundID represents the ID of a sound effect or a music track.
+type SoundID uint8
+
+const (
+	SoundFootstep SoundID = iota + 1
+	SoundPlace
+	SoundWin
+	SoundDraw
+	SoundCrackle
+	SoundAmbient
+)
+
+// Audio plays the music and the sound effects of the game.
+// sounds holds the decoded signed 16 bit stereo samples of every sound.
+// music is the looping ambient track.
+// emitters are looping sounds attached to a world position, like the lantern crackle.
+// oneShots are the players of the sound effects still playing, closed once they finish.
+// lastPos and stepDistance track how far each player walked since its last footstep.
+type Audio struct {
+	context      *audio.Context
+	sounds       map[SoundID][]byte
+	music        *audio.Player
+	emitters     []*soundEmitter
+	oneShots     []*audio.Player
+	lastPos      map[*Player]Vec2
+	stepDistance map[*Player]float64
+}
+
+// soundEmitter is a looping sound played at a fixed world position.
+type soundEmitter struct {
+	pos    Vec2
+	stream *spatialStream
+	player *audio.Player
+}
+
+// NewAudio creates the audio context and decodes every sound of soundManifest.
+func NewAudio() (*Audio, error) {
+	// the audio context can only be created once per process
+	context := audio.CurrentContext()
+	if context == nil {
+		context = audio.NewContext(AudioSampleRate)
+	}
+
+	sounds, err := loadSounds(context.SampleRate())
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Audio{
+		context:      context,
+		sounds:       sounds,
+		music:        nil,
+		emitters:     nil,
+		oneShots:     nil,
+		lastPos:      make(map[*Player]Vec2),
+		stepDistance: make(map[*Player]float64),
+	}
+
+	ambient := sounds[SoundAmbient]
+	loop := audio.NewInfiniteLoop(bytes.NewReader(ambient), int64(len(ambient)))
+	a.music, err = context.NewPlayer(loop)
+	if err != nil {
+		return nil, fmt.Errorf("create music player: %w", err)
+	}
+
+	return a, nil
+}
+
+// AddEmitter starts a looping positional sound at the given world position.
+func (a *Audio) AddEmitter(id SoundID, pos Vec2) error {
+	data := a.sounds[id]
+	if len(data) == 0 {
+		return fmt.Errorf("unknown sound %d", id)
+	}
+
+	loop := audio.NewInfiniteLoop(bytes.NewReader(data), int64(len(data)))
+	stream := newSpatialStream(loop, 0, 0)
+
+	player, err := a.context.NewPlayer(stream)
+	if err != nil {
+		return fmt.Errorf("create emitter player: %w", err)
+	}
+
+	a.emitters = append(a.emitters, &soundEmitter{pos: pos, stream: stream, player: player})
+	return nil
+}
+
+// ClearEmitters stops and removes every positional sound, before the map decorations are replaced.
+func (a *Audio) ClearEmitters() {
+	for _, e := range a.emitters {
+		e.player.Pause()
+		if err := e.player.Close(); err != nil {
+			slog.Warn("close emitter player", "error", err)
+		}
+	}
+	a.emitters = nil
+}
+
+// Update keeps the music and the emitters playing, updates their volume and panning
+// from the current player's point of view and plays the footsteps of both players.
+// The players of the finished sound effects are closed.
+func (a *Audio) Update(g *Game) {
+	a.closeFinishedOneShots()
+
+	listener := g.currentPlayer
+	if listener == nil {
+		return
+	}
+
+	a.music.SetVolume(g.settings.MusicGain() * AudioMusicGain)
+	if !a.music.IsPlaying() {
+		a.music.Play()
+	}
+
+	effects := g.settings.EffectsGain()
+	for _, e := range a.emitters {
+		left, right := spatialGains(listener.pos, listener.dir, e.pos)
+		e.stream.SetGains(left, right)
+		e.player.SetVolume(effects * AudioEmitterGain)
+		if !e.player.IsPlaying() {
+			e.player.Play()
+		}
+	}
+
+	a.updateFootsteps(g, g.playerX, listener)
+	a.updateFootsteps(g, g.playerO, listener)
+}
+
+// Play plays a sound effect without positioning, at the same volume on both ears.
+func (a *Audio) Play(g *Game, id SoundID) {
+	a.playWithGains(id, 1, 1, g.settings.EffectsGain())
+}
+
+// PlayAt plays a sound effect emitted at the given world position, heard by the current player.
+func (a *Audio) PlayAt(g *Game, id SoundID, pos Vec2) {
+	listener := g.currentPlayer
+	if listener == nil {
+		return
+	}
+
+	left, right := spatialGains(listener.pos, listener.dir, pos)
+	a.playWithGains(id, left, right, g.settings.EffectsGain())
+}
+
+// updateFootsteps plays a footstep each time the player walked AudioFootstepStride units.
+// the listener hears its own footsteps centered, the other player's footsteps are positional.
+func (a *Audio) updateFootsteps(g *Game, p, listener *Player) {
+	if p == nil {
+		return
+	}
+
+	last, ok := a.lastPos[p]
+	a.lastPos[p] = p.pos
+	if !ok {
+		return
+	}
+
+	a.stepDistance[p] += p.pos.Sub(last).Len()
+	if a.stepDistance[p] < AudioFootstepStride {
+		return
+	}
+	a.stepDistance[p] = 0
+
+	if p == listener {
+		a.playWithGains(SoundFootstep, AudioOwnFootstepGain, AudioOwnFootstepGain, g.settings.EffectsGain())
+		return
+	}
+	a.PlayAt(g, SoundFootstep, p.pos)
+}
+
+// playWithGains starts a one shot player for the sound with the given channel gains and volume.
+func (a *Audio) playWithGains(id SoundID, left, right, volume float64) {
+	if volume <= 0 || (left <= 0 && right <= 0) {
+		return
+	}
+
+	data := a.sounds[id]
+	if len(data) == 0 {
+		return
+	}
+
+	player, err := a.context.NewPlayer(newSpatialStream(bytes.NewReader(data), left, right))
+	if err != nil {
+		slog.Warn("play sound", "sound", id, "error", err)
+		return
+	}
+	player.SetVolume(volume)
+	player.Play()
+	a.oneShots = append(a.oneShots, player)
+}
+
+// closeFinishedOneShots closes the players of the sound effects that stopped playing.
+func (a *Audio) closeFinishedOneShots() {
+	a.oneShots = slices.DeleteFunc(a.oneShots, func(p *audio.Player) bool {
+		if p.IsPlaying() {
+			return false
+		}
+		if err := p.Close(); err != nil {
+			slog.Warn("close sound player", "error", err)
+		}
+		return true
+	})
+}
+
+// loadSounds decodes every sound of soundManifest into signed 16 bit stereo samples at the given sample rate.
+// wav and ogg files are supported, the decoder is chosen from the file extension.
+func loadSounds(sampleRate int) (map[SoundID][]byte, error) {
+	out := make(map[SoundID][]byte, len(soundManifest))
+
+	for id, filename := range soundManifest {
+		fullPath := filepath.ToSlash(filepath.Join(SoundFolder, filename))
+
+		data, err := soundsFS.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("open %q: %w", fullPath, err)
+		}
+
+		stream, err := decodeSound(filename, sampleRate, bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode %q: %w", fullPath, err)
+		}
+
+		pcm, err := io.ReadAll(stream)
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", fullPath, err)
+		}
+
+		out[id] = pcm
+	}
+
+	return out, nil
+}
+
+// decodeSound returns a signed 16 bit stereo stream for a wav or ogg file.
+func decodeSound(filename string, sampleRate int, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".wav":
+		stream, err := wav.DecodeWithSampleRate(sampleRate, r)
+		if err != nil {
+			return nil, fmt.Errorf("wav: %w", err)
+		}
+		return stream, nil
+	case ".ogg":
+		stream, err := vorbis.DecodeWithSampleRate(sampleRate, r)
+		if err != nil {
+			return nil, fmt.Errorf("ogg: %w", err)
+		}
+		return stream, nil
+	default:
+		return nil, fmt.Errorf("unsupported sound format %q", filepath.Ext(filename))
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// audioBytesPerFrame is the size of one stereo frame of signed 16 bit samples.
+const audioBytesPerFrame = 4
+
+// spatialGains returns the left and right channel gains of a sound emitted at source
+// and heard by a listener standing at listenerPos and looking along listenerDir.
+// The volume falls off linearly with the distance and reaches zero at AudioMaxDistance.
+// Panning uses an equal power law so a sound straight ahead is as loud as one on the side.
+func spatialGains(listenerPos, listenerDir, source Vec2) (float64, float64) {
+	offset := source.Sub(listenerPos)
+	distance := offset.Len()
+	if distance >= AudioMaxDistance {
+		return 0, 0
+	}
+
+	attenuation := 1 - distance/AudioMaxDistance
+
+	// pan is -1 when the source is on the left and 1 when it is on the right
+	// the perpendicular of the direction points to the right of the screen, like the camera plane
+	pan := 0.0
+	if distance > 0 {
+		pan = offset.Scale(1 / distance).Dot(listenerDir.Normalize().Perp())
+	}
+
+	angle := (pan + 1) * math.Pi / 4 //nolint:mnd // map [-1, 1] to [0, pi/2]
+	return math.Cos(angle) * attenuation, math.Sin(angle) * attenuation
+}
+
+// spatialStream applies per channel gains to a signed 16 bit stereo stream.
+// The gains can be changed from the game loop while the audio goroutine reads the stream.
+type spatialStream struct {
+	src   io.Reader
+	left  atomic.Uint64
+	right atomic.Uint64
+}
+
+// newSpatialStream wraps src with the given initial gains.
+func newSpatialStream(src io.Reader, left, right float64) *spatialStream {
+	s := &spatialStream{src: src}
+	s.SetGains(left, right)
+	return s
+}
+
+// SetGains updates the left and right channel gains.
+func (s *spatialStream) SetGains(left, right float64) {
+	s.left.Store(math.Float64bits(left))
+	s.right.Store(math.Float64bits(right))
+}
+
+// Read reads whole frames from the source stream and scales each channel by its gain.
+func (s *spatialStream) Read(p []byte) (int, error) {
+	n := len(p) - len(p)%audioBytesPerFrame
+	if n == 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	read, err := io.ReadFull(s.src, p[:n])
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	read -= read % audioBytesPerFrame
+
+	left := math.Float64frombits(s.left.Load())
+	right := math.Float64frombits(s.right.Load())
+
+	//nolint:mnd // a stereo frame is two 16 bit samples
+	for i := 0; i < read; i += audioBytesPerFrame {
+		scaleSample(p[i:i+2], left)
+		scaleSample(p[i+2:i+4], right)
+	}
+
+	return read, err
+}
+
+// scaleSample multiplies the little endian signed 16 bit sample in b by gain.
+func scaleSample(b []byte, gain float64) {
+	v := float64(int16(binary.LittleEndian.Uint16(b))) * gain
+	v = math.Max(math.MinInt16, math.Min(math.MaxInt16, v))
+	binary.LittleEndian.PutUint16(b, uint16(int16(v)))
+}
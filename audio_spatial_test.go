@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestSpatialGains_Panning(t *testing.T) {
+	// listener looks east, so +Y (south) is on its right
+	pos := Vec2{5, 5}
+	dir := Vec2{1, 0}
+
+	left, right := spatialGains(pos, dir, Vec2{5, 7})
+	if right <= left {
+		t.Errorf("source on the right: left=%v right=%v", left, right)
+	}
+
+	left, right = spatialGains(pos, dir, Vec2{5, 3})
+	if left <= right {
+		t.Errorf("source on the left: left=%v right=%v", left, right)
+	}
+
+	left, right = spatialGains(pos, dir, Vec2{8, 5})
+	if math.Abs(left-right) > 1e-9 {
+		t.Errorf("source ahead should be centered: left=%v right=%v", left, right)
+	}
+}
+
+func TestSpatialGains_Attenuation(t *testing.T) {
+	pos := Vec2{0, 0}
+	dir := Vec2{0, 1}
+
+	nearL, nearR := spatialGains(pos, dir, Vec2{0, 1})
+	farL, farR := spatialGains(pos, dir, Vec2{0, AudioMaxDistance / 2})
+	if nearL+nearR <= farL+farR {
+		t.Errorf("near source should be louder: near=%v far=%v", nearL+nearR, farL+farR)
+	}
+
+	outL, outR := spatialGains(pos, dir, Vec2{0, AudioMaxDistance})
+	if outL != 0 || outR != 0 {
+		t.Errorf("source out of range should be silent: left=%v right=%v", outL, outR)
+	}
+
+	// a sound on the listener is centered at full volume
+	l, r := spatialGains(pos, dir, pos)
+	if math.Abs(l-r) > 1e-9 || math.Abs(l*l+r*r-1) > 1e-9 {
+		t.Errorf("sound on the listener: left=%v right=%v", l, r)
+	}
+}
+
+func TestSpatialStream_AppliesGains(t *testing.T) {
+	src := make([]byte, 2*audioBytesPerFrame)
+	for i := 0; i < len(src); i += 2 {
+		binary.LittleEndian.PutUint16(src[i:], uint16(int16(1000)))
+	}
+
+	s := newSpatialStream(bytes.NewReader(src), 0.5, 0)
+	out, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != len(src) {
+		t.Fatalf("read %d bytes, want %d", len(out), len(src))
+	}
+
+	for i := 0; i < len(out); i += audioBytesPerFrame {
+		left := int16(binary.LittleEndian.Uint16(out[i:]))
+		right := int16(binary.LittleEndian.Uint16(out[i+2:]))
+		if left != 500 || right != 0 {
+			t.Errorf("frame %d = (%d, %d), want (500, 0)", i/audioBytesPerFrame, left, right)
+		}
+	}
+}
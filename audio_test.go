@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// testdata/sounds/short.ogg is test_tooshort.ogg of the ebiten audio/vorbis tests, a mono clip at 44100 Hz.
+func TestDecodeSound_Ogg(t *testing.T) {
+	data, err := os.ReadFile("testdata/sounds/short.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the extension picks the decoder, whatever its case
+	stream, err := decodeSound("SHORT.OGG", AudioSampleRate, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decodeSound() = %v", err)
+	}
+	pcm, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 19856 mono samples become 16 bit stereo frames of 4 bytes
+	if len(pcm) != 19856*4 {
+		t.Errorf("decoded %d bytes, want 79424", len(pcm))
+	}
+}
+
+func TestDecodeSound_Errors(t *testing.T) {
+	if _, err := decodeSound("step.mp3", AudioSampleRate, bytes.NewReader(nil)); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if _, err := decodeSound("step.ogg", AudioSampleRate, bytes.NewReader([]byte("not an ogg"))); err == nil {
+		t.Error("expected an error for a broken ogg file")
+	}
+}
@@ -3,36 +3,58 @@
 
 package main
 
+import "cmp"
+
 type Board [GridSize][GridSize]PlayerSymbol
 
+// BoardCell is a cell of the board, by column and row, and by level in the cube where 0 is the flat board.
+type BoardCell struct {
+	Col, Row, Level int
+}
+
+// BoardLine is a row, a column or a diagonal of the board, or a line through the levels of the cube.
+type BoardLine [GridSize]BoardCell
+
+// boardLines returns the lines a player fills to win: the rows, the columns and both diagonals.
+func boardLines() []BoardLine {
+	lines := make([]BoardLine, 0, GridSize*Two+Two)
+	var diagonal, antiDiagonal BoardLine
+	for i := range GridSize {
+		var row, col BoardLine
+		for j := range GridSize {
+			row[j] = BoardCell{Col: j, Row: i}
+			col[j] = BoardCell{Col: i, Row: j}
+		}
+		lines = append(lines, row, col)
+		diagonal[i] = BoardCell{Col: i, Row: i}
+		antiDiagonal[i] = BoardCell{Col: GridSize - 1 - i, Row: i}
+	}
+	return append(lines, diagonal, antiDiagonal)
+}
+
 // Reset clears the board to its initial empty state.
 func (b *Board) Reset() {
 	*b = Board{}
 }
 
-// CheckWinner checks the board for a winner and returns the winning symbol and whether the game is over.
-func (b *Board) CheckWinner() PlayerSymbol {
-	for i := range GridSize {
-		// check rows
-		if w := b.checkLine(b[i][0], b[i][1], b[i][2]); w != PlayerSymbolNone {
-			return w
+// CheckWinner checks the board for a line filled with a single symbol
+// and returns the winning symbol and the cells of the line, PlayerSymbolNone and nil when there is none.
+func (b *Board) CheckWinner() (PlayerSymbol, []BoardCell) {
+	for _, line := range boardLines() {
+		first := b[line[0].Row][line[0].Col]
+		if first == PlayerSymbolNone {
+			continue
 		}
 
-		// check columns
-		if w := b.checkLine(b[0][i], b[1][i], b[2][i]); w != PlayerSymbolNone {
-			return w
+		full := true
+		for _, c := range line[1:] {
+			full = full && b[c.Row][c.Col] == first
+		}
+		if full {
+			return first, line[:]
 		}
 	}
-
-	// check diagonals
-	if w := b.checkLine(b[0][0], b[1][1], b[2][2]); w != PlayerSymbolNone {
-		return w
-	}
-	if w := b.checkLine(b[0][2], b[1][1], b[2][0]); w != PlayerSymbolNone {
-		return w
-	}
-
-	return PlayerSymbolNone
+	return PlayerSymbolNone, nil
 }
 
 // IsFull returns true if the board is full (no empty cells).
@@ -47,10 +69,105 @@ func (b *Board) IsFull() bool {
 	return true
 }
 
-// checkLine checks if the three given cells form a winning line.
-func (b *Board) checkLine(c1, c2, c3 PlayerSymbol) PlayerSymbol {
-	if c1 != PlayerSymbolNone && c1 == c2 && c2 == c3 {
-		return c1
+// Cube is the 3×3×3 board of the cube mode, a Board per level from the bottom up.
+// Modes playing on a flat board only use its first level.
+type Cube [GridSize]Board
+
+// cubeLines returns the lines a player fills to win in the cube: the 8 lines of each level,
+// the 9 columns through the levels and the 16 diagonals crossing them, 49 in all.
+// A line is only kept from its first cell, so each one is listed once.
+func cubeLines() []BoardLine {
+	var lines []BoardLine
+	for _, d := range cubeDirections() {
+		for level := range GridSize {
+			for row := range GridSize {
+				for col := range GridSize {
+					start := BoardCell{Col: col, Row: row, Level: level}
+					if line, ok := cubeLine(start, d); ok {
+						lines = append(lines, line)
+					}
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// cubeDirections returns the 13 directions of a line in the cube, one of each pair of opposite directions.
+func cubeDirections() []BoardCell {
+	var dirs []BoardCell
+	for dl := -1; dl <= 1; dl++ {
+		for dr := -1; dr <= 1; dr++ {
+			for dc := -1; dc <= 1; dc++ {
+				// the first non zero step is positive
+				first := cmp.Or(dl, dr, dc)
+				if first > 0 {
+					dirs = append(dirs, BoardCell{Col: dc, Row: dr, Level: dl})
+				}
+			}
+		}
+	}
+	return dirs
+}
+
+// cubeLine returns the line from start in the direction d, ok is false when it leaves the cube
+// or when start is not the first cell of the line.
+func cubeLine(start, d BoardCell) (BoardLine, bool) {
+	if isCubeCell(BoardCell{Col: start.Col - d.Col, Row: start.Row - d.Row, Level: start.Level - d.Level}) {
+		return BoardLine{}, false
+	}
+
+	var line BoardLine
+	for i := range GridSize {
+		line[i] = BoardCell{Col: start.Col + i*d.Col, Row: start.Row + i*d.Row, Level: start.Level + i*d.Level}
+		if !isCubeCell(line[i]) {
+			return BoardLine{}, false
+		}
+	}
+	return line, true
+}
+
+// isCubeCell returns true if the cell is inside the cube.
+func isCubeCell(c BoardCell) bool {
+	return c.Col >= 0 && c.Col < GridSize && c.Row >= 0 && c.Row < GridSize && c.Level >= 0 && c.Level < GridSize
+}
+
+// Reset clears every level of the cube.
+func (c *Cube) Reset() {
+	*c = Cube{}
+}
+
+// At returns the symbol on the cell.
+func (c *Cube) At(cell BoardCell) PlayerSymbol {
+	return c[cell.Level][cell.Row][cell.Col]
+}
+
+// CheckWinner checks the 49 lines of the cube for a line filled with a single symbol
+// and returns the winning symbol and the cells of the line, PlayerSymbolNone and nil when there is none.
+func (c *Cube) CheckWinner() (PlayerSymbol, []BoardCell) {
+	for _, line := range cubeLines() {
+		first := c.At(line[0])
+		if first == PlayerSymbolNone {
+			continue
+		}
+
+		full := true
+		for _, cell := range line[1:] {
+			full = full && c.At(cell) == first
+		}
+		if full {
+			return first, line[:]
+		}
+	}
+	return PlayerSymbolNone, nil
+}
+
+// IsFull returns true if no cell of any level is empty.
+func (c *Cube) IsFull() bool {
+	for i := range c {
+		if !c[i].IsFull() {
+			return false
+		}
 	}
-	return PlayerSymbolNone
+	return true
 }
@@ -41,7 +41,7 @@ func TestBoard_CheckWinner_Rows(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.board.CheckWinner(); got != tt.expected {
+			if got, _ := tt.board.CheckWinner(); got != tt.expected {
 				t.Errorf("CheckWinner() = %v, want %v", got, tt.expected)
 			}
 		})
@@ -67,7 +67,7 @@ func TestBoard_CheckWinner_Columns(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.board.CheckWinner(); got != tt.expected {
+			if got, _ := tt.board.CheckWinner(); got != tt.expected {
 				t.Errorf("CheckWinner() = %v, want %v", got, tt.expected)
 			}
 		})
@@ -102,7 +102,7 @@ func TestBoard_CheckWinner_Diagonals(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.board.CheckWinner(); got != tt.expected {
+			if got, _ := tt.board.CheckWinner(); got != tt.expected {
 				t.Errorf("CheckWinner() = %v, want %v", got, tt.expected)
 			}
 		})
@@ -141,3 +141,144 @@ func TestBoard_Reset(t *testing.T) {
 		}
 	}
 }
+
+func TestBoardCellAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		pos    Vec2
+		cx, cy int
+		ok     bool
+	}{
+		{name: "Top left room", pos: Vec2{1.5, 1.5}, cx: 0, cy: 0, ok: true},
+		{name: "Centre room", pos: Vec2{10.5, 10.5}, cx: 1, cy: 1, ok: true},
+		{name: "Bottom right room", pos: Vec2{20.5, 15.2}, cx: 2, cy: 2, ok: true},
+		{name: "Outside right", pos: Vec2{21.5, 1.5}, ok: false},
+		{name: "Negative", pos: Vec2{-0.5, 1.5}, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cx, cy, ok := boardCellAt(tt.pos)
+			if ok != tt.ok || (ok && (cx != tt.cx || cy != tt.cy)) {
+				t.Errorf("boardCellAt(%v) = %d, %d, %v, want %d, %d, %v", tt.pos, cx, cy, ok, tt.cx, tt.cy, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBoard_CheckWinner_Line(t *testing.T) {
+	b := Board{
+		{PlayerSymbolO, PlayerSymbolX, PlayerSymbolX},
+		{PlayerSymbolNone, PlayerSymbolX, PlayerSymbolO},
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolNone},
+	}
+
+	winner, line := b.CheckWinner()
+	if winner != PlayerSymbolX {
+		t.Fatalf("CheckWinner() winner = %v, want X", winner)
+	}
+	want := []BoardCell{{Col: 2, Row: 0}, {Col: 1, Row: 1}, {Col: 0, Row: 2}}
+	if len(line) != len(want) {
+		t.Fatalf("CheckWinner() line = %v, want %v", line, want)
+	}
+	for i := range want {
+		if line[i] != want[i] {
+			t.Errorf("CheckWinner() line = %v, want %v", line, want)
+		}
+	}
+
+	b[1][1] = PlayerSymbolO
+	if winner, line = b.CheckWinner(); winner != PlayerSymbolNone || line != nil {
+		t.Errorf("CheckWinner() = %v, %v, want no winner and no line", winner, line)
+	}
+}
+
+func TestPlayerSymbol_Opponent(t *testing.T) {
+	if PlayerSymbolX.Opponent() != PlayerSymbolO || PlayerSymbolO.Opponent() != PlayerSymbolX {
+		t.Error("X and O should be opponents")
+	}
+	if PlayerSymbolNone.Opponent() != PlayerSymbolNone {
+		t.Error("an empty cell has no opponent")
+	}
+}
+
+func TestCubeLines(t *testing.T) {
+	lines := cubeLines()
+	if len(lines) != 49 {
+		t.Fatalf("cubeLines() = %d lines, want 49", len(lines))
+	}
+
+	seen := map[[2]BoardCell]bool{}
+	for _, line := range lines {
+		first, last := line[0], line[GridSize-1]
+		if seen[[2]BoardCell{first, last}] || seen[[2]BoardCell{last, first}] {
+			t.Errorf("line %v is listed twice", line)
+		}
+		seen[[2]BoardCell{first, last}] = true
+
+		// the same step between every cell of the line
+		dc, dr, dl := line[1].Col-first.Col, line[1].Row-first.Row, line[1].Level-first.Level
+		for i, c := range line {
+			want := BoardCell{Col: first.Col + i*dc, Row: first.Row + i*dr, Level: first.Level + i*dl}
+			if c != want || !isCubeCell(c) {
+				t.Errorf("line %v is not straight inside the cube", line)
+				break
+			}
+		}
+	}
+}
+
+func TestCube_CheckWinner(t *testing.T) {
+	tests := []struct {
+		name string
+		line []BoardCell
+	}{
+		{name: "row of the top level", line: []BoardCell{{0, 2, 2}, {1, 2, 2}, {2, 2, 2}}},
+		{name: "column through the levels", line: []BoardCell{{1, 1, 0}, {1, 1, 1}, {1, 1, 2}}},
+		{name: "diagonal through the levels", line: []BoardCell{{0, 2, 0}, {1, 2, 1}, {2, 2, 2}}},
+		{name: "space diagonal", line: []BoardCell{{2, 0, 0}, {1, 1, 1}, {0, 2, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Cube
+			for _, cell := range tt.line {
+				c[cell.Level][cell.Row][cell.Col] = PlayerSymbolO
+			}
+
+			winner, line := c.CheckWinner()
+			if winner != PlayerSymbolO || len(line) != GridSize {
+				t.Fatalf("CheckWinner() = %v, %v, want O on %v", winner, line, tt.line)
+			}
+			for _, cell := range line {
+				if c.At(cell) != PlayerSymbolO {
+					t.Errorf("CheckWinner() line %v, want %v", line, tt.line)
+				}
+			}
+
+			c[tt.line[1].Level][tt.line[1].Row][tt.line[1].Col] = PlayerSymbolX
+			if winner, _ = c.CheckWinner(); winner != PlayerSymbolNone {
+				t.Errorf("CheckWinner() = %v with a broken line, want none", winner)
+			}
+		})
+	}
+}
+
+func TestCube_IsFull(t *testing.T) {
+	var c Cube
+	for level := range c {
+		for row := range GridSize {
+			for col := range GridSize {
+				c[level][row][col] = PlayerSymbolX
+			}
+		}
+	}
+	if !c.IsFull() {
+		t.Error("Expected cube to be full")
+	}
+
+	c[2][1][1] = PlayerSymbolNone
+	if c.IsFull() {
+		t.Error("Expected cube to NOT be full with an empty cell on the top level")
+	}
+}
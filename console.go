@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// ConsoleCommand is a command that can be typed in the developer console.
+// Usage describes the arguments, it is shown by help and on usage errors.
+// Run executes the command and returns a message to print.
+// Complete optionally returns the candidates for the first argument.
+type ConsoleCommand struct {
+	Usage    string
+	Run      func(g *Game, args []string) (string, error)
+	Complete func(g *Game) []string
+}
+
+// Console is the drop-down developer console toggled with the tilde key.
+// input is the line being typed, output the lines printed so far.
+// history holds the executed lines, historyIndex is the line recalled with the arrow keys
+// (len(history) when typing a new line).
+type Console struct {
+	open         bool
+	input        string
+	output       []string
+	history      []string
+	historyIndex int
+	commands     map[string]ConsoleCommand
+}
+
+// errUsage is returned by commands called with invalid arguments.
+var errUsage = errors.New("usage")
+
+// NewConsole creates a console with the default commands registered.
+func NewConsole() *Console {
+	c := &Console{
+		open:         false,
+		input:        "",
+		output:       nil,
+		history:      nil,
+		historyIndex: 0,
+		commands:     make(map[string]ConsoleCommand),
+	}
+	registerDefaultCommands(c)
+	return c
+}
+
+// Register adds a command to the console, replacing any command with the same name.
+func (c *Console) Register(name string, cmd ConsoleCommand) {
+	c.commands[name] = cmd
+}
+
+// Toggle opens or closes the console.
+func (c *Console) Toggle() {
+	c.open = !c.open
+	c.input = ""
+	c.historyIndex = len(c.history)
+}
+
+// IsOpen returns true while the console is shown and grabs the keyboard.
+func (c *Console) IsOpen() bool {
+	return c.open
+}
+
+// Update handles typing, history, completion and execution while the console is open.
+func (c *Console) Update(g *Game) {
+	if !c.open {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		c.Toggle()
+		return
+	}
+
+	for _, r := range ebiten.AppendInputChars(nil) {
+		c.input += string(r)
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(c.input) > 0:
+		c.input = trimLastRune(c.input)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		c.complete(g)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		c.recall(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		c.recall(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		line := c.input
+		c.input = ""
+		c.Execute(g, line)
+	}
+}
+
+// Execute runs a command line, stores it in the history and prints its result.
+func (c *Console) Execute(g *Game, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	c.history = append(c.history, line)
+	c.historyIndex = len(c.history)
+	c.print("> " + line)
+
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	cmd, ok := c.commands[name]
+	if !ok {
+		c.print(fmt.Sprintf("unknown command %q, type help", name))
+		return
+	}
+
+	msg, err := cmd.Run(g, args)
+	switch {
+	case errors.Is(err, errUsage):
+		c.print("usage: " + name + " " + cmd.Usage)
+	case err != nil:
+		c.print("error: " + err.Error())
+	case msg != "":
+		for l := range strings.SplitSeq(msg, "\n") {
+			c.print(l)
+		}
+	}
+}
+
+// Draw renders the console over the top of the screen.
+func (c *Console) Draw(screen *ebiten.Image, g *Game) {
+	if !c.open || screen == nil || g == nil {
+		return
+	}
+
+	vector.FillRect(screen, 0, 0, float32(WindowSizeX), float32(ConsoleHeightPixels), ColorConsoleFill, false)
+	vector.FillRect(
+		screen,
+		0,
+		float32(ConsoleHeightPixels),
+		float32(WindowSizeX),
+		float32(HudBorderWidthPixels),
+		ColorHUDBorder,
+		false,
+	)
+
+	// last output lines, oldest on top, followed by the prompt
+	visible := ConsoleHeightPixels/ConsoleLineHeightPixels - 1
+	start := max(0, len(c.output)-visible)
+
+	y := float64(ConsolePaddingPixels)
+	for _, line := range c.output[start:] {
+		g.drawText(screen, line, ConsolePaddingPixels, y, ColorHUDText)
+		y += ConsoleLineHeightPixels
+	}
+	g.drawText(screen, "> "+c.input+"_", ConsolePaddingPixels, y, ColorConsolePrompt)
+}
+
+// print appends a line to the output, dropping the oldest lines.
+func (c *Console) print(line string) {
+	c.output = append(c.output, line)
+	if len(c.output) > ConsoleMaxOutputLines {
+		c.output = c.output[len(c.output)-ConsoleMaxOutputLines:]
+	}
+}
+
+// recall replaces the input with an older (step -1) or newer (step 1) history line.
+func (c *Console) recall(step int) {
+	if len(c.history) == 0 {
+		return
+	}
+
+	c.historyIndex = clampInt(c.historyIndex+step, 0, len(c.history))
+	if c.historyIndex == len(c.history) {
+		c.input = ""
+		return
+	}
+	c.input = c.history[c.historyIndex]
+}
+
+// complete completes the command name, or the first argument when the command knows its candidates.
+// when several candidates match, the common prefix is completed and the candidates are printed.
+func (c *Console) complete(g *Game) {
+	name, arg, hasArg := strings.Cut(c.input, " ")
+
+	var candidates []string
+	prefix := name
+	if hasArg {
+		cmd, ok := c.commands[name]
+		if !ok || cmd.Complete == nil {
+			return
+		}
+		candidates = cmd.Complete(g)
+		prefix = arg
+	} else {
+		for n := range c.commands {
+			candidates = append(candidates, n)
+		}
+	}
+
+	matches := completionMatches(candidates, prefix)
+	if len(matches) == 0 {
+		return
+	}
+
+	completed := longestCommonPrefix(matches)
+	if len(matches) == 1 {
+		completed += " "
+	} else {
+		c.print(strings.Join(matches, "  "))
+	}
+
+	if hasArg {
+		c.input = name + " " + completed
+	} else {
+		c.input = completed
+	}
+}
+
+// completionMatches returns the sorted candidates starting with prefix.
+func completionMatches(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+// longestCommonPrefix returns the longest prefix shared by all the words.
+func longestCommonPrefix(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// parseFloats parses every argument as a float, the number of arguments must be n.
+func parseFloats(args []string, n int) ([]float64, error) {
+	if len(args) != n {
+		return nil, errUsage
+	}
+
+	values := make([]float64, n)
+	for i, arg := range args {
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", arg)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// parseSymbol parses "X", "O" or "-" (empty) in any case.
+func parseSymbol(s string) (PlayerSymbol, error) {
+	switch strings.ToUpper(s) {
+	case "X":
+		return PlayerSymbolX, nil
+	case "O":
+		return PlayerSymbolO, nil
+	case "-", ".":
+		return PlayerSymbolNone, nil
+	}
+	return PlayerSymbolNone, fmt.Errorf("invalid symbol %q, expected X, O or -", s)
+}
+
+// trimLastRune removes the last character of s, whatever its length in bytes.
+func trimLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
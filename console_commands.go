@@ -0,0 +1,288 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// registerDefaultCommands registers the built-in developer commands.
+func registerDefaultCommands(c *Console) {
+	c.Register("help", ConsoleCommand{
+		Usage: "",
+		Run: func(_ *Game, _ []string) (string, error) {
+			lines := make([]string, 0, len(c.commands))
+			for _, name := range slices.Sorted(maps.Keys(c.commands)) {
+				lines = append(lines, name+" "+c.commands[name].Usage)
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+		Complete: nil,
+	})
+
+	c.Register("teleport", ConsoleCommand{
+		Usage:    "<x> <y>",
+		Run:      runTeleport,
+		Complete: nil,
+	})
+
+	c.Register("setcell", ConsoleCommand{
+		Usage:    "<row> <col> <X|O|->",
+		Run:      runSetCell,
+		Complete: nil,
+	})
+
+	c.Register("noclip", ConsoleCommand{
+		Usage:    "",
+		Run:      runNoclip,
+		Complete: nil,
+	})
+
+	c.Register("fov", ConsoleCommand{
+		Usage:    "<radians>",
+		Run:      runFOV,
+		Complete: nil,
+	})
+
+	c.Register("renderer", ConsoleCommand{
+		Usage: "<gpu|software>",
+		Run:   runRenderer,
+		Complete: func(_ *Game) []string {
+			return []string{string(RendererGPU), string(RendererSoftware)}
+		},
+	})
+
+	c.Register("turntimer", ConsoleCommand{
+		Usage: "<seconds> [skip|random|forfeit]",
+		Run:   runTurnTimer,
+		Complete: func(_ *Game) []string {
+			return []string{string(TurnTimeoutSkip), string(TurnTimeoutRandom), string(TurnTimeoutForfeit)}
+		},
+	})
+
+	c.Register("reset", ConsoleCommand{
+		Usage: "",
+		Run: func(g *Game, _ []string) (string, error) {
+			g.resetBoard()
+			return "board reset", nil
+		},
+		Complete: nil,
+	})
+
+	c.Register("win", ConsoleCommand{
+		Usage:    "<X|O|->",
+		Run:      runWin,
+		Complete: nil,
+	})
+
+	c.Register("loadmap", ConsoleCommand{
+		Usage:    "<file>",
+		Run:      runLoadMap,
+		Complete: nil,
+	})
+
+	c.Register("spawn", ConsoleCommand{
+		Usage: "<type> <x> <y>",
+		Run:   runSpawn,
+		Complete: func(g *Game) []string {
+			return slices.Collect(maps.Keys(g.SpriteTypes()))
+		},
+	})
+}
+
+// runTeleport moves the current player to the given world position.
+func runTeleport(g *Game, args []string) (string, error) {
+	v, err := parseFloats(args, 2) //nolint:mnd // x and y
+	if err != nil {
+		return "", err
+	}
+
+	if errT := g.Teleport(g.currentPlayer, Vec2{X: v[0], Y: v[1]}); errT != nil {
+		return "", errT
+	}
+	return fmt.Sprintf("teleported to %.2f, %.2f", v[0], v[1]), nil
+}
+
+// runSetCell sets or clears a board cell without changing turns.
+func runSetCell(g *Game, args []string) (string, error) {
+	//nolint:mnd // row, column and symbol
+	if len(args) != 3 {
+		return "", errUsage
+	}
+
+	row, errR := strconv.Atoi(args[0])
+	col, errC := strconv.Atoi(args[1])
+	if errR != nil || errC != nil {
+		return "", errUsage
+	}
+
+	symbol, err := parseSymbol(args[2])
+	if err != nil {
+		return "", err
+	}
+
+	// replace whatever is in the cell
+	if errClear := g.ClearCell(col, row); errClear != nil {
+		return "", errClear
+	}
+	if symbol != PlayerSymbolNone {
+		if errP := g.PlaceMark(col, row, symbol); errP != nil {
+			return "", errP
+		}
+	}
+	return fmt.Sprintf("cell %d,%d = %s", row, col, symbol), nil
+}
+
+// runNoclip toggles walking through walls for the current player.
+func runNoclip(g *Game, _ []string) (string, error) {
+	p := g.currentPlayer
+	p.noclip = !p.noclip
+	if p.noclip {
+		return "noclip on", nil
+	}
+
+	// do not leave the player stuck in a wall
+	if pos, ok := g.levelMap(p.level).NearestWalkable(p.pos); ok {
+		p.pos = pos
+	}
+	return "noclip off", nil
+}
+
+// runFOV changes the field of view setting.
+func runFOV(g *Game, args []string) (string, error) {
+	v, err := parseFloats(args, 1)
+	if err != nil {
+		return "", err
+	}
+
+	s := g.settings
+	s.FOV = v[0]
+	if errA := g.ApplySettings(s); errA != nil {
+		return "", errA
+	}
+	return fmt.Sprintf("fov = %.2f", g.settings.FOV), nil
+}
+
+// runRenderer selects the world renderer, without argument it prints the current one.
+func runRenderer(g *Game, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "renderer = " + string(g.settings.Renderer), nil
+	case 1:
+	default:
+		return "", errUsage
+	}
+
+	s := g.settings
+	s.Renderer = RendererKind(args[0])
+	if s.Renderer != RendererGPU && s.Renderer != RendererSoftware {
+		return "", errUsage
+	}
+	if errA := g.ApplySettings(s); errA != nil {
+		return "", errA
+	}
+	return "renderer = " + args[0], nil
+}
+
+// runTurnTimer sets the turn time limit and optionally the timeout, without argument it prints them.
+// The countdown of a turn being played restarts with the new limit.
+func runTurnTimer(g *Game, args []string) (string, error) {
+	s := g.settings
+	switch len(args) {
+	case 0:
+		return turnTimerStatus(s), nil
+	case 1:
+	case Two:
+		s.TurnTimeout = TurnTimeoutKind(args[1])
+		if !isValidTurnTimeout(s.TurnTimeout) {
+			return "", errUsage
+		}
+	default:
+		return "", errUsage
+	}
+
+	v, err := parseFloats(args[:1], 1)
+	if err != nil {
+		return "", err
+	}
+	s.TurnTimeLimit = v[0]
+	if errA := g.ApplySettings(s); errA != nil {
+		return "", errA
+	}
+
+	if g.state == StatePlaying {
+		g.timer.Start(g.settings.TurnTimeLimit)
+	}
+	return turnTimerStatus(g.settings), nil
+}
+
+// turnTimerStatus describes the turn timer settings.
+func turnTimerStatus(s Settings) string {
+	if s.TurnTimeLimit <= 0 {
+		return "turn timer off"
+	}
+	return fmt.Sprintf("turn timer = %.0fs, %s on timeout", s.TurnTimeLimit, s.TurnTimeout)
+}
+
+// runWin ends the round with the given winner, "-" ends it with a draw.
+// Only a round being played can end, the names must be confirmed and the levels built first.
+func runWin(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage
+	}
+	if g.state != StatePlaying {
+		return "", errors.New("no round is being played")
+	}
+
+	symbol, err := parseSymbol(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	// a forced end has no winning line to show
+	g.handleGameEnd(RoundResult{Over: true, Winner: symbol, Line: nil, Second: PlayerSymbolNone})
+	return "round over", nil
+}
+
+// runLoadMap replaces the world map with a json map file and starts a new round on it,
+// like the map editor does: the rooms of the claimed cells may be elsewhere in the new map.
+func runLoadMap(g *Game, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage
+	}
+
+	m, err := LoadMapFile(args[0])
+	if err != nil {
+		return "", err
+	}
+	if errS := g.SetMap(m); errS != nil {
+		return "", errS
+	}
+	g.resetBoard()
+	g.mapPath = args[0]
+	return fmt.Sprintf("loaded %dx%d map", m.Width(), m.Height()), nil
+}
+
+// runSpawn adds a decoration sprite at the given world position.
+func runSpawn(g *Game, args []string) (string, error) {
+	//nolint:mnd // type, x and y
+	if len(args) != 3 {
+		return "", errUsage
+	}
+
+	v, err := parseFloats(args[1:], 2) //nolint:mnd // x and y
+	if err != nil {
+		return "", err
+	}
+
+	if errS := g.SpawnSprite(args[0], Vec2{X: v[0], Y: v[1]}); errS != nil {
+		return "", errS
+	}
+	return fmt.Sprintf("spawned %s at %.2f, %.2f", args[0], v[0], v[1]), nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLongestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{words: nil, want: ""},
+		{words: []string{"spawn"}, want: "spawn"},
+		{words: []string{"setcell", "spawn"}, want: "s"},
+		{words: []string{"skull", "skeleton"}, want: "sk"},
+		{words: []string{"fov", "noclip"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := longestCommonPrefix(tt.words); got != tt.want {
+			t.Errorf("longestCommonPrefix(%v) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestConsole_CompleteCommandName(t *testing.T) {
+	c := NewConsole()
+
+	c.input = "tel"
+	c.complete(nil)
+	if c.input != "teleport " {
+		t.Errorf("input = %q, want %q", c.input, "teleport ")
+	}
+
+	c.input = "spawn sk"
+	c.complete(nil)
+	if c.input != "spawn skull " {
+		t.Errorf("input = %q, want %q", c.input, "spawn skull ")
+	}
+}
+
+func TestConsole_ExecuteAndHistory(t *testing.T) {
+	c := NewConsole()
+
+	var got []string
+	c.Register("echo", ConsoleCommand{
+		Usage: "<words>",
+		Run: func(_ *Game, args []string) (string, error) {
+			got = args
+			if len(args) == 0 {
+				return "", errUsage
+			}
+			return "ok", nil
+		},
+		Complete: nil,
+	})
+
+	c.Execute(nil, "  echo a b ")
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("args = %v, want [a b]", got)
+	}
+	if last := c.output[len(c.output)-1]; last != "ok" {
+		t.Errorf("last output = %q, want %q", last, "ok")
+	}
+
+	c.Execute(nil, "echo")
+	if last := c.output[len(c.output)-1]; last != "usage: echo <words>" {
+		t.Errorf("last output = %q, want the usage", last)
+	}
+
+	c.Execute(nil, "nope")
+
+	c.recall(-1)
+	if c.input != "nope" {
+		t.Errorf("recall(-1) = %q, want %q", c.input, "nope")
+	}
+	c.recall(-1)
+	c.recall(-1)
+	c.recall(-1)
+	if c.input != "echo a b" {
+		t.Errorf("recall past the oldest line = %q, want %q", c.input, "echo a b")
+	}
+	c.recall(1)
+	c.recall(1)
+	c.recall(1)
+	if c.input != "" {
+		t.Errorf("recall past the newest line = %q, want an empty input", c.input)
+	}
+}
+
+func TestParseSymbol(t *testing.T) {
+	for in, want := range map[string]PlayerSymbol{"x": PlayerSymbolX, "O": PlayerSymbolO, "-": PlayerSymbolNone} {
+		if got, err := parseSymbol(in); err != nil || got != want {
+			t.Errorf("parseSymbol(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseSymbol("Z"); err == nil {
+		t.Error("expected an error for an invalid symbol")
+	}
+}
+
+func TestTrimLastRune(t *testing.T) {
+	for in, want := range map[string]string{"": "", "tp": "t", "café": "caf", "x→": "x"} {
+		if got := trimLastRune(in); got != want {
+			t.Errorf("trimLastRune(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGame_TeleportNeedsWalkableTile(t *testing.T) {
+	p := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	g := &Game{worldMap: NewMap(), playerX: p, currentPlayer: p}
+
+	if err := g.Teleport(p, Vec2{X: 0.5, Y: 0.5}); err == nil || p.pos != (Vec2{X: 1.5, Y: 1.5}) {
+		t.Errorf("Teleport() into a wall = %v, moved to %v", err, p.pos)
+	}
+	p.noclip = true
+	if err := g.Teleport(p, Vec2{X: 0.5, Y: 0.5}); err != nil {
+		t.Errorf("Teleport() into a wall with noclip = %v", err)
+	}
+	if err := g.Teleport(p, Vec2{X: -1, Y: 0.5}); err == nil {
+		t.Error("Teleport() outside of the map succeeded with noclip")
+	}
+}
+
+func TestRunWin_OnlyWhilePlaying(t *testing.T) {
+	g := &Game{state: StateNameInput}
+	if _, err := runWin(g, []string{"X"}); err == nil || g.state != StateNameInput {
+		t.Errorf("runWin() during the name input = %v, state %d", err, g.state)
+	}
+}
+
+func TestRunLoadMap_StartsANewRound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m.json")
+	if err := SaveMapFile(path, NewMap()); err != nil {
+		t.Fatal(err)
+	}
+	g := &Game{
+		state:    StatePlaying,
+		playerX:  NewPlayer(1.5, 1.5, PlayerSymbolX, "X"),
+		playerO:  NewPlayer(9.5, 1.5, PlayerSymbolO, "O"),
+		worldMap: NewMap(),
+	}
+	if err := g.PlaceMark(0, 0, PlayerSymbolX); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runLoadMap(g, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if g.board[0][0][0] != PlayerSymbolNone || slices.ContainsFunc(g.sprites, func(s *Sprite) bool {
+		return isMarkTexture(s.TextureID)
+	}) {
+		t.Errorf("board = %v, want a new round without the marks of the previous map", g.board[0])
+	}
+}
@@ -3,7 +3,10 @@
 
 package main
 
-import "image/color"
+import (
+	"image/color"
+	"math"
+)
 
 const (
 	WindowSizeX      = 1280
@@ -15,8 +18,10 @@ const (
 	DeltaTime        = 1.0 / TPS
 	GameOverDuration = 3.0
 
+	TurnTransitionDuration = 1.2 // seconds the camera takes to move to the next player
+	TurnBannerHeight       = 120
+
 	GridSize        = 3
-	MapGridSize     = 22
 	Margin          = 10
 	LineWidth       = 2
 	HeaderY         = 20
@@ -30,13 +35,12 @@ const (
 	NameInputX          = 10
 	NameInputY          = 40
 	NameInputLineHeight = 40
+	NameInputModeLine   = 4 // line of the game mode, below the names and the help
 
-	MinimapGridCellSize      = 8
-	MinimapWidth             = MapGridSize * MinimapGridCellSize
-	MinimapHeight            = MapGridSize * MinimapGridCellSize
+	MinimapSizePixels        = 176 // side of the minimap square, the map is scaled to fit in it
 	MinimapPadding           = 10
 	MinimapBorderWidth       = 2
-	MinimapPosX              = WindowSizeX - MinimapWidth - MinimapPadding - MinimapBorderWidth
+	MinimapPosX              = WindowSizeX - MinimapSizePixels - MinimapPadding - MinimapBorderWidth
 	MinimapPosY              = MinimapPadding
 	MinimapPlayerRadius      = 2
 	MinimapPlayerDiameter    = MinimapPlayerRadius * 2
@@ -44,6 +48,19 @@ const (
 	MinimapPlayerArrowAAngle = 0.5
 	MinimapPlayerArrowLength = 20
 	MinimapPlayerArrowWidth  = 2
+	MinimapMarkScale         = 0.6 // size of the marks relative to their room
+	MinimapSpriteScale       = 0.8 // size of the sprite icons relative to a tile
+	MinimapFOVConeLength     = 4.0 // tiles
+	MinimapWinLineWidth      = 3
+	MinimapLevelTabSize      = 22 // side of the squares of the level selector, left of the minimap
+	MinimapLevelTabGap       = 4
+
+	WinGlowStrength    = 0.8           // extra brightness of the winning marks at the top of a pulse
+	WinGlowPulseSpeed  = 6.0           // radians per second
+	FlyoverRadiusScale = 0.3           // distance of the game over camera to the mark, relative to the room size
+	FlyoverSweep       = math.Pi / Two // radians the camera turns around each winning mark
+
+	ExplorationRays = 64 // rays cast per tick to reveal the tiles seen by the current player
 
 	PlayerFOV                        = 1.58
 	PlayerMovementSpeed              = 5.0 // units per second
@@ -55,11 +72,38 @@ const (
 	DefaultPlayerOSpawnX = 11.5
 	DefaultPlayerOSpawnY = 11.5
 
-	MapRoomStride = 7
-	MaxRayIter    = MapGridSize * MapGridSize
+	MapRoomStride             = 7
+	MapSpriteAnimationStagger = 0.9 // seconds between the animations of consecutive map sprites
+
+	TeleporterRadius = 0.8 // tiles, distance from a pad at which a player can take it
+	TeleporterScale  = 0.8
+	TeleporterZ      = 0.2
+
+	SubscriptMax     = 9    // largest subscript, the digits sheet has 1 to 9
+	SubscriptScale   = 0.35 // size of the subscript digit, relative to the sprite
+	SubscriptOffsetX = 0.3  // offset of the digit center to the right of the sprite center, relative to the sprite
+	SubscriptBottom  = 0.85 // bottom of the digit below the sprite top, relative to the sprite
 
-	TextureSize   = 64
-	TextureFolder = "assets/textures"
+	QuantumSpookyFade   = 0.5 // see-through spooky marks, in superposition
+	QuantumSpookyScale  = 0.5
+	QuantumSpookySpread = 1.5 // tiles, distance of the spooky marks to the center of their room
+	QuantumSecondPoints = 0.5 // points of the second player to fill a line at the same collapse
+
+	TextureSize         = 64 // reference frame size, textures of other sizes are drawn as large in the world
+	TextureFolder       = "assets/textures"
+	TextureAtlasMaxSize = 4096 // largest atlas side, supported by WebGL on every browser
+	WallSideShade       = 0.7  // brightness of the walls facing north or south
+
+	SoundFolder          = "assets/sounds"
+	AudioSampleRate      = 44100
+	AudioMaxDistance     = 14.0 // world units, sounds further away are silent
+	AudioFootstepStride  = 0.9  // world units walked between two footsteps
+	AudioOwnFootstepGain = 0.4
+	AudioEmitterGain     = 0.6
+	AudioMusicGain       = 0.5
+	AudioDefaultVolume   = 0.8
+	AudioMinVolume       = 0.0
+	AudioMaxVolume       = 1.0
 
 	HudHeightPixels      = 100
 	HudTopLeftYPixels    = WindowSizeY - HudHeightPixels
@@ -71,12 +115,38 @@ const (
 
 	HudTextLineStepPixels = 26
 
+	HudKeysColumnWidthPixels = 280
+
+	DebugPanelX       = 10
+	DebugPanelY       = 10
+	DebugPanelWidth   = 620
+	DebugPanelPadding = 8
+	DebugRayFanStep   = 16 // one ray of the fan every n screen columns
+
+	ConsoleHeightPixels     = 300
+	ConsoleLineHeightPixels = 20
+	ConsolePaddingPixels    = 10
+	ConsoleMaxOutputLines   = 200
+
+	MapScreenMarginPixels       = 20
+	MapScreenStatusHeightPixels = 60
+	EditorSpritePickRadius      = 0.5 // tiles, distance at which a click grabs a sprite
+	EditorSpriteIconScale       = 0.8 // size of the sprite icons relative to a tile
+	EditorSpawnRadiusScale      = 0.3 // radius of the spawn markers relative to a tile
+	EditorDefaultMapPath        = "map.json"
+
 	HudSquarePanelSizePixels = HudHeightPixels
 
-	HudNamePanelWidthPixels = 520
+	HudNamePanelWidthPixels = 420
 	HudKeysPanelWidthPixels = WindowSizeX -
-		HudSquarePanelSizePixels*2 -
+		HudSquarePanelSizePixels*3 -
 		HudNamePanelWidthPixels
+	HudBoardLineWidthPixels = 1
+	HudBoardWinLineWidth    = 3
+	HudBoardCurrentWidth    = 2
+	HudCollapsePromptHeight = 56
+
+	HudTurnTimerWarningSeconds = 5.0
 
 	HalfTile = 0.5
 	Two      = 2
@@ -90,6 +160,11 @@ func defaultPlayerOSpawn() Vec2 {
 	return Vec2{X: DefaultPlayerOSpawnX, Y: DefaultPlayerOSpawnY}
 }
 
+// defaultPlayerDir is the direction players look at when they spawn.
+func defaultPlayerDir() Vec2 {
+	return Vec2{X: -1, Y: 0}
+}
+
 //nolint:gochecknoglobals // colors
 var (
 	ColorBackground         = color.RGBA{30, 30, 30, 100}
@@ -98,10 +173,26 @@ var (
 
 	ColorMinimapBorder = color.RGBA{0, 0, 0, 100}
 	ColorMinimapWall   = color.RGBA{200, 200, 200, 100}
+	ColorMinimapFloor  = color.RGBA{60, 60, 60, 100}
+
+	ColorMinimapClaimedX    = color.RGBA{249, 77, 0, 60}
+	ColorMinimapClaimedO    = color.RGBA{86, 229, 252, 60}
+	ColorOverviewBackground = color.RGBA{0, 0, 0, 220}
+	ColorMinimapFOV         = color.RGBA{255, 230, 150, 120}
+	ColorMinimapWinLine     = color.RGBA{255, 215, 0, 255}
+	// ColorMinimapLevel fills the tab of the level shown by the minimap, ColorMinimapLevelTab the other ones.
+	ColorMinimapLevel    = color.RGBA{120, 120, 140, 220}
+	ColorMinimapLevelTab = color.RGBA{0, 0, 0, 160}
 
 	ColorMinimapPlayerX = color.RGBA{249, 77, 0, 100}
 	ColorMinimapPlayerO = color.RGBA{86, 229, 252, 100}
 
+	ColorEditorFloor        = color.RGBA{45, 45, 50, 255}
+	ColorEditorWall         = color.RGBA{150, 150, 150, 255}
+	ColorEditorRoom         = color.RGBA{230, 200, 90, 255}
+	ColorEditorSelectedRoom = color.RGBA{120, 230, 120, 255}
+	ColorEditorMissing      = color.RGBA{230, 40, 200, 255}
+
 	ColorCeiling = color.RGBA{25, 25, 30, 255}
 	ColorFloor   = color.RGBA{20, 18, 18, 255}
 
@@ -109,6 +200,24 @@ var (
 	ColorHUDBorder = color.RGBA{120, 120, 140, 255}
 	ColorHUDFill   = color.RGBA{10, 10, 10, 220}
 	ColorHUDText   = color.RGBA{220, 220, 220, 255}
+	// ColorHUDWinLine crosses the winning cells on the board panel.
+	ColorHUDWinLine = color.RGBA{255, 215, 0, 255}
+	// ColorHUDBoardCurrent outlines the board cell of the current player, ColorHUDBoardWin and ColorHUDBoardBlock
+	// fill the cells where they would win or must block with the assist.
+	ColorHUDBoardCurrent = color.RGBA{255, 255, 255, 220}
+	ColorHUDBoardWin     = color.RGBA{80, 200, 90, 110}
+	ColorHUDBoardBlock   = color.RGBA{220, 60, 50, 110}
+	// ColorHUDBoardCollapse outlines the cells a cycle collapses into in the quantum mode,
+	// ColorHUDBoardPicked the first room of a spooky mark.
+	ColorHUDBoardCollapse = color.RGBA{170, 120, 255, 255}
+	ColorHUDBoardPicked   = color.RGBA{255, 215, 0, 220}
+	// ColorHUDTimerWarning draws the turn countdown during its last seconds.
+	ColorHUDTimerWarning = color.RGBA{240, 70, 60, 255}
+
+	ColorDebugRay = color.RGBA{255, 220, 0, 120}
+
+	ColorConsoleFill   = color.RGBA{0, 0, 0, 230}
+	ColorConsolePrompt = color.RGBA{255, 220, 0, 255}
 )
 
 //nolint:gochecknoglobals // texture manifest
@@ -127,4 +236,30 @@ var imageManifest = map[TextureID]string{
 	Chains:           "chains.png",
 	Light:            "lantern.png",
 	WasdKeys:         "wasd-keys.png",
+	Poof:             "poof.png",
+	Teleporter:       "teleporter.png",
+	Digits:           "digits.png",
+}
+
+// textureFrames is the number of frames side by side in the sprite sheets of imageManifest.
+// Other textures have a single frame, the frame size is the image width divided by the frames.
+//
+//nolint:gochecknoglobals // texture frames manifest
+var textureFrames = map[TextureID]int{
+	PlayerXCharacter: 8,
+	PlayerOCharacter: 8,
+	Digits:           9,
+}
+
+//nolint:gochecknoglobals // sound manifest
+var soundManifest = map[SoundID]string{
+	// effects
+	SoundFootstep: "footstep.wav",
+	SoundPlace:    "place.wav",
+	SoundWin:      "win.wav",
+	SoundDraw:     "draw.wav",
+	SoundCrackle:  "crackle.wav",
+
+	// music
+	SoundAmbient: "ambient.wav",
 }
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DebugOverlay shows raycaster and player state on top of the game.
+// enabled toggles the overlay, while it is disabled the world does not record anything.
+// stats is filled by the world while rendering the current frame.
+type DebugOverlay struct {
+	enabled bool
+	stats   RenderStats
+}
+
+// RenderStats holds what the world rendered during the last frame.
+// centreHit is the ray hit of the screen column in the middle of the view.
+// rayEnds are the hit points of every DebugRayFanStep-th column, used to draw the ray fan on the minimap.
+// visibleSprites is the number of sprites that were in front of the camera and on screen.
+// renderTime is the time spent in World.Draw, for the gpu renderer it excludes the gpu work.
+type RenderStats struct {
+	centreHit      RayHit
+	rayEnds        []Vec2
+	visibleSprites int
+	renderTime     time.Duration
+}
+
+// Toggle enables or disables the overlay.
+func (d *DebugOverlay) Toggle() {
+	d.enabled = !d.enabled
+}
+
+// frameStats returns the stats to fill for a new frame, or nil when the overlay is disabled.
+func (d *DebugOverlay) frameStats() *RenderStats {
+	if d == nil || !d.enabled {
+		return nil
+	}
+
+	d.stats.centreHit = noHit()
+	d.stats.rayEnds = d.stats.rayEnds[:0]
+	d.stats.visibleSprites = 0
+	d.stats.renderTime = 0
+	return &d.stats
+}
+
+// Draw renders the overlay text panel in the top left corner.
+func (d *DebugOverlay) Draw(screen *ebiten.Image, g *Game) {
+	if !d.enabled || screen == nil || g == nil || g.currentPlayer == nil {
+		return
+	}
+
+	p := g.currentPlayer
+
+	cellLine := "Cell: -"
+	if cx, cy, ok := g.levelMap(p.level).BoardCellAt(p.pos); ok {
+		cellLine = fmt.Sprintf("Cell: row %d col %d level %d", cy, cx, p.level)
+	}
+
+	hitLine := "Ray: no hit"
+	if hit := d.stats.centreHit; hit.hit {
+		hitLine = fmt.Sprintf(
+			"Ray: (%d,%d) d=%.2f wallX=%.2f side=%d",
+			hit.cellX,
+			hit.cellY,
+			hit.distance,
+			hit.wallX,
+			hit.side,
+		)
+	}
+
+	lines := []string{
+		fmt.Sprintf("TPS: %.1f  FPS: %.1f", ebiten.ActualTPS(), ebiten.ActualFPS()),
+		fmt.Sprintf("Pos: %.2f, %.2f", p.pos.X, p.pos.Y),
+		fmt.Sprintf("Dir: %.2f, %.2f (%.0f deg)", p.dir.X, p.dir.Y, math.Atan2(p.dir.Y, p.dir.X)*180/math.Pi),
+		cellLine,
+		hitLine,
+		fmt.Sprintf("Sprites: %d", d.stats.visibleSprites),
+		fmt.Sprintf(
+			"Renderer (F4): %s %.2fms",
+			g.settings.Renderer,
+			float64(d.stats.renderTime.Microseconds())/float64(time.Millisecond/time.Microsecond),
+		),
+	}
+
+	vector.FillRect(
+		screen,
+		float32(DebugPanelX),
+		float32(DebugPanelY),
+		float32(DebugPanelWidth),
+		float32(len(lines)*HudTextLineStepPixels+DebugPanelPadding*Two),
+		ColorHUDFill,
+		false,
+	)
+
+	drawTextLines(g, screen, float64(DebugPanelX+DebugPanelPadding), float64(DebugPanelY+DebugPanelPadding), lines)
+}
+
+// drawRayFan draws the recorded rays on the minimap, from the player to each hit point.
+func (d *DebugOverlay) drawRayFan(screen *ebiten.Image, v minimapView, p *Player) {
+	if !d.enabled || p == nil {
+		return
+	}
+
+	px, py := v.toScreen(p.pos)
+	for _, end := range d.stats.rayEnds {
+		ex, ey := v.toScreen(end)
+		vector.StrokeLine(screen, px, py, ex, ey, 1, ColorDebugRay, false)
+	}
+}
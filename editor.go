@@ -0,0 +1,466 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// EditorTool is what the mouse does in the map editor.
+type EditorTool int
+
+const (
+	// EditorToolWalls paints the selected wall tile with the left button and erases walls with the right one.
+	EditorToolWalls EditorTool = iota
+	// EditorToolSprites places or drags decorations with the left button and deletes them with the right one.
+	EditorToolSprites
+	// EditorToolRooms draws the room of the selected board cell with a left drag, the right button removes a room.
+	EditorToolRooms
+	// EditorToolSpawns sets the spawn of player X with the left button and of player O with the right one.
+	EditorToolSpawns
+)
+
+func (t EditorTool) String() string {
+	switch t {
+	case EditorToolWalls:
+		return "walls"
+	case EditorToolSprites:
+		return "sprites"
+	case EditorToolRooms:
+		return "rooms"
+	case EditorToolSpawns:
+		return "spawns"
+	}
+	return "?"
+}
+
+// Editor is the map editor shown instead of the world in StateEditor.
+// draft is the map being edited, it replaces the game map when the editor is closed.
+// tile, spriteType and cell are the palette selections of the walls, sprites and rooms tools,
+// cell being the board cell row*GridSize+col.
+// dragSprite is the index of the sprite moved by the mouse, -1 when none.
+// roomStart is the first corner of the room being drawn while drawingRoom is set.
+// status is the result of the last action, shown in the status bar.
+type Editor struct {
+	draft Map
+	tool  EditorTool
+
+	tile       TileID
+	spriteType string
+	cell       int
+
+	dragSprite  int
+	roomStart   TileCoord
+	drawingRoom bool
+
+	status string
+}
+
+// NewEditor creates a closed editor.
+func NewEditor() *Editor {
+	return &Editor{
+		draft:       Map{},
+		tool:        EditorToolWalls,
+		tile:        1,
+		spriteType:  "light",
+		cell:        0,
+		dragSprite:  -1,
+		roomStart:   TileCoord{},
+		drawingRoom: false,
+		status:      "",
+	}
+}
+
+// Open starts editing a copy of the map.
+func (e *Editor) Open(m Map) {
+	e.draft = m.Clone()
+	e.dragSprite = -1
+	e.drawingRoom = false
+	e.status = "editing, F2 to play"
+}
+
+// toggleEditor opens the map editor, or closes it and plays on the edited map.
+// names are typed with the keyboard, the editor cannot be opened before they are confirmed.
+func (g *Game) toggleEditor() {
+	switch g.state {
+	case StateNameInput:
+	case StatePlaying, StateGameOver, StateTurnTransition:
+		g.editor.Open(g.worldMap)
+		g.state = StateEditor
+	case StateEditor:
+		g.closeEditor()
+	}
+}
+
+// closeEditor applies the edited map and starts a new round on it.
+// The editor stays open with the reason in its status bar when the map is invalid.
+func (g *Game) closeEditor() {
+	if err := g.SetMap(g.editor.draft); err != nil {
+		g.editor.status = "invalid map: " + err.Error()
+		return
+	}
+	g.resetBoard()
+}
+
+// saveEditor writes the edited map to the file it was loaded from, EditorDefaultMapPath otherwise.
+func (g *Game) saveEditor() {
+	path := g.mapPath
+	if path == "" {
+		path = EditorDefaultMapPath
+	}
+
+	if err := SaveMapFile(path, g.editor.draft); err != nil {
+		g.editor.status = "save failed: " + err.Error()
+		return
+	}
+	g.mapPath = path
+	g.editor.status = "saved " + path
+}
+
+func (g *Game) updateEditor() error {
+	e := g.editor
+
+	// 1-4: select the tool
+	for i, key := range []ebiten.Key{ebiten.KeyDigit1, ebiten.KeyDigit2, ebiten.KeyDigit3, ebiten.KeyDigit4} {
+		if inpututil.IsKeyJustPressed(key) {
+			e.tool = EditorTool(i)
+			e.drawingRoom = false
+		}
+	}
+
+	// [ ] or the mouse wheel: previous or next palette entry of the tool
+	_, wheel := ebiten.Wheel()
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) || wheel > 0 {
+		e.cyclePalette(g, -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) || wheel < 0 {
+		e.cyclePalette(g, 1)
+	}
+
+	// Ctrl+S: save the map
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) &&
+		(ebiten.IsKeyPressed(ebiten.KeyControlLeft) || ebiten.IsKeyPressed(ebiten.KeyControlRight)) {
+		g.saveEditor()
+	}
+
+	cx, cy := ebiten.CursorPosition()
+	pos, inside := editorWorldPos(e.draft, cx, cy)
+	if !inside {
+		// a drag ends wherever the button is released
+		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+			e.dragSprite = -1
+			e.drawingRoom = false
+		}
+		return nil
+	}
+
+	switch e.tool {
+	case EditorToolWalls:
+		e.updateWalls(pos)
+	case EditorToolSprites:
+		e.updateSprites(pos)
+	case EditorToolRooms:
+		e.updateRooms(pos)
+	case EditorToolSpawns:
+		e.updateSpawns(pos)
+	}
+	return nil
+}
+
+// cyclePalette selects the previous (delta -1) or next (delta 1) palette entry of the current tool.
+func (e *Editor) cyclePalette(g *Game, delta int) {
+	switch e.tool {
+	case EditorToolWalls:
+		tiles := editorWallTiles(g.assets.Textures)
+		if len(tiles) > 0 {
+			e.tile = tiles[cycleIndex(slices.Index(tiles, e.tile), len(tiles), delta)]
+		}
+	case EditorToolSprites:
+		names := editorSpriteTypes(g.SpriteTypes())
+		if len(names) > 0 {
+			e.spriteType = names[cycleIndex(slices.Index(names, e.spriteType), len(names), delta)]
+		}
+	case EditorToolRooms:
+		e.cell = cycleIndex(e.cell, GridSize*GridSize, delta)
+	case EditorToolSpawns:
+	}
+}
+
+func (e *Editor) updateWalls(pos Vec2) {
+	x, y := int(pos.X), int(pos.Y)
+
+	var err error
+	switch {
+	case ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft):
+		err = e.paintTile(x, y, e.tile)
+	case ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight):
+		err = e.paintTile(x, y, TileEmpty)
+	}
+	if err != nil {
+		e.status = err.Error()
+	}
+}
+
+func (e *Editor) updateSprites(pos Vec2) {
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		if i, ok := e.spriteAt(pos); ok {
+			e.dragSprite = i
+			return
+		}
+		e.draft.Sprites = append(e.draft.Sprites, MapSprite{Type: e.spriteType, Position: pos})
+		e.dragSprite = len(e.draft.Sprites) - 1
+		e.status = fmt.Sprintf("placed %s at (%.1f,%.1f)", e.spriteType, pos.X, pos.Y)
+	case inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft):
+		e.dragSprite = -1
+	case ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && e.dragSprite >= 0:
+		e.draft.Sprites[e.dragSprite].Position = pos
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight):
+		if i, ok := e.spriteAt(pos); ok {
+			e.status = "removed " + e.draft.Sprites[i].Type
+			e.draft.Sprites = slices.Delete(e.draft.Sprites, i, i+1)
+		}
+	}
+}
+
+func (e *Editor) updateRooms(pos Vec2) {
+	tile := TileCoord{X: int(pos.X), Y: int(pos.Y)}
+
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		e.roomStart = tile
+		e.drawingRoom = true
+	case inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && e.drawingRoom:
+		e.drawingRoom = false
+		r := roomBetween(e.roomStart, tile, e.cell%GridSize, e.cell/GridSize)
+		e.setRoom(r)
+		e.status = fmt.Sprintf("room of cell (%d,%d) set to %dx%d tiles", r.Row, r.Col, r.W, r.H)
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight):
+		if i, ok := e.draft.RoomAt(tile.X, tile.Y); ok {
+			e.status = fmt.Sprintf("removed the room of cell (%d,%d)", e.draft.Rooms[i].Row, e.draft.Rooms[i].Col)
+			e.draft.Rooms = slices.Delete(e.draft.Rooms, i, i+1)
+		}
+	}
+}
+
+func (e *Editor) updateSpawns(pos Vec2) {
+	symbol := PlayerSymbolNone
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		symbol = PlayerSymbolX
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight):
+		symbol = PlayerSymbolO
+	}
+	if symbol == PlayerSymbolNone {
+		return
+	}
+
+	if err := e.setSpawn(symbol, pos); err != nil {
+		e.status = err.Error()
+		return
+	}
+	e.status = fmt.Sprintf("spawn of %s set to (%.1f,%.1f)", symbol, pos.X, pos.Y)
+}
+
+// paintTile sets the tile (x, y) of the draft. Border tiles can change texture but cannot be erased,
+// the face textures of an erased wall are dropped.
+func (e *Editor) paintTile(x, y int, tile TileID) error {
+	current, outOfBounds := e.draft.GetTileID(x, y)
+	if outOfBounds || current == tile {
+		return nil
+	}
+
+	onBorder := x == 0 || y == 0 || x == e.draft.Width()-1 || y == e.draft.Height()-1
+	if onBorder && tile == TileEmpty {
+		return errors.New("border tiles must stay walls")
+	}
+
+	e.draft.Tiles[y][x] = tile
+	if tile == TileEmpty {
+		delete(e.draft.Faces, TileCoord{X: x, Y: y})
+	}
+	return nil
+}
+
+// spriteAt returns the index of the draft sprite closest to pos within EditorSpritePickRadius.
+func (e *Editor) spriteAt(pos Vec2) (int, bool) {
+	best, bestDist := -1, EditorSpritePickRadius*EditorSpritePickRadius
+	for i, s := range e.draft.Sprites {
+		if d := s.Position.Sub(pos).Len2(); d <= bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best, best >= 0
+}
+
+// setRoom adds the room to the draft, replacing the previous room of its cell and the rooms it overlaps.
+func (e *Editor) setRoom(r Room) {
+	e.draft.Rooms = slices.DeleteFunc(e.draft.Rooms, func(o Room) bool {
+		return (o.Col == r.Col && o.Row == r.Row) || o.Overlaps(r)
+	})
+	e.draft.Rooms = append(e.draft.Rooms, r)
+}
+
+// setSpawn moves the spawn of the player, which must be on an empty tile.
+func (e *Editor) setSpawn(symbol PlayerSymbol, pos Vec2) error {
+	if !e.draft.IsWalkable(pos) {
+		return fmt.Errorf("spawn of %s must be on an empty tile", symbol)
+	}
+	if e.draft.Spawns == nil {
+		e.draft.Spawns = make(map[PlayerSymbol]Vec2)
+	}
+	e.draft.Spawns[symbol] = pos
+	return nil
+}
+
+// roomBetween returns the room of the board cell (col, row) spanning both corner tiles.
+func roomBetween(a, b TileCoord, col, row int) Room {
+	x, y := min(a.X, b.X), min(a.Y, b.Y)
+	return Room{
+		X: x, Y: y,
+		W: max(a.X, b.X) - x + 1, H: max(a.Y, b.Y) - y + 1,
+		Col: col, Row: row,
+	}
+}
+
+// cycleIndex returns the index delta steps after i in a list of n entries, wrapping around.
+// An unknown index (-1) starts from the first entry.
+func cycleIndex(i, n, delta int) int {
+	if i < 0 {
+		return 0
+	}
+	return ((i+delta)%n + n) % n
+}
+
+// editorWallTiles returns the tiles that can be painted: those with a wall texture, in order.
+func editorWallTiles(textures TextureMap) []TileID {
+	var tiles []TileID
+	for id := TileID(1); id < firstSpriteTextureID; id++ {
+		if _, ok := textures[TextureID(id)]; ok {
+			tiles = append(tiles, id)
+		}
+	}
+	return tiles
+}
+
+// editorSpriteTypes returns the sorted names of the decoration types that can be placed,
+// one shot effects like "poof" would disappear as soon as the map is played and are left out.
+func editorSpriteTypes(types map[string]SpriteType) []string {
+	names := make([]string, 0, len(types))
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		if anim := types[name].Animation; anim != nil && anim.Mode == AnimationOneShot {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// editorWorldPos returns the world position under the screen pixel (sx, sy), ok is false outside of the map.
+func editorWorldPos(m Map, sx, sy int) (Vec2, bool) {
+	originX, originY, tile := fullScreenMapLayout(m)
+	if tile == 0 {
+		return Vec2{}, false
+	}
+
+	pos := Vec2{X: (float64(sx) - originX) / tile, Y: (float64(sy) - originY) / tile}
+	return pos, m.Contains(pos)
+}
+
+// Draw renders the edited map full screen with its rooms, sprites and spawns, and the status bar.
+func (e *Editor) Draw(screen *ebiten.Image, g *Game) {
+	originX, originY, tile := fullScreenMapLayout(e.draft)
+	toScreen := func(p Vec2) (float32, float32) {
+		return float32(originX + p.X*tile), float32(originY + p.Y*tile)
+	}
+
+	for y, row := range e.draft.Tiles {
+		for x, id := range row {
+			sx, sy := toScreen(Vec2{X: float64(x), Y: float64(y)})
+			if id == TileEmpty {
+				vector.FillRect(screen, sx, sy, float32(tile), float32(tile), ColorEditorFloor, false)
+				continue
+			}
+			drawTextureIcon(screen, g.assets.Textures[TextureID(id)], float64(sx), float64(sy), tile, ColorEditorWall)
+		}
+	}
+
+	e.drawRooms(screen, g, toScreen, tile)
+
+	iconSize := tile * EditorSpriteIconScale
+	for _, s := range e.draft.Sprites {
+		sx, sy := toScreen(s.Position)
+		texture := Texture{}
+		if t, ok := g.SpriteTypes()[s.Type]; ok {
+			texture = g.assets.Textures[t.TextureID]
+		}
+		half := iconSize / Two
+		drawTextureIcon(screen, texture, float64(sx)-half, float64(sy)-half, iconSize, ColorEditorMissing)
+	}
+
+	for _, symbol := range []PlayerSymbol{PlayerSymbolX, PlayerSymbolO} {
+		col := ColorMinimapPlayerX
+		if symbol == PlayerSymbolO {
+			col = ColorMinimapPlayerO
+		}
+		sx, sy := toScreen(e.draft.Spawn(symbol))
+		vector.FillCircle(screen, sx, sy, float32(tile*EditorSpawnRadiusScale), col, true)
+		g.drawTextWithFace(screen, symbol.String(), float64(sx), float64(sy), Center, ColorHUDText,
+			g.assets.NormalTextFace, TextLineSpacing)
+	}
+
+	e.drawStatus(screen, g)
+}
+
+// drawRooms outlines the rooms with their board cell, the rooms of the selected cell and the one being drawn
+// are highlighted.
+func (e *Editor) drawRooms(screen *ebiten.Image, g *Game, toScreen func(Vec2) (float32, float32), tile float64) {
+	rooms := e.draft.Rooms
+	if e.drawingRoom {
+		cx, cy := ebiten.CursorPosition()
+		if pos, ok := editorWorldPos(e.draft, cx, cy); ok {
+			end := TileCoord{X: int(pos.X), Y: int(pos.Y)}
+			rooms = append(slices.Clone(rooms), roomBetween(e.roomStart, end, e.cell%GridSize, e.cell/GridSize))
+		}
+	}
+
+	for _, r := range rooms {
+		col := ColorEditorRoom
+		if e.tool == EditorToolRooms && r.Col == e.cell%GridSize && r.Row == e.cell/GridSize {
+			col = ColorEditorSelectedRoom
+		}
+
+		sx, sy := toScreen(Vec2{X: float64(r.X), Y: float64(r.Y)})
+		vector.StrokeRect(screen, sx, sy, float32(float64(r.W)*tile), float32(float64(r.H)*tile), Two, col, false)
+		g.drawTextWithFace(screen, fmt.Sprintf("%d,%d", r.Row, r.Col), float64(sx)+Two, float64(sy)+Two,
+			TopLeft, col, g.assets.NormalTextFace, TextLineSpacing)
+	}
+}
+
+// drawStatus draws the tool, its palette selection and the last action below the map.
+func (e *Editor) drawStatus(screen *ebiten.Image, g *Game) {
+	selection := ""
+	switch e.tool {
+	case EditorToolWalls:
+		selection = fmt.Sprintf("tile %d", e.tile)
+	case EditorToolSprites:
+		selection = e.spriteType
+	case EditorToolRooms:
+		selection = fmt.Sprintf("cell (%d,%d)", e.cell/GridSize, e.cell%GridSize)
+	case EditorToolSpawns:
+		selection = "left X, right O"
+	}
+
+	y := float64(WindowSizeY - MapScreenStatusHeightPixels)
+	help := fmt.Sprintf("[1-4] tool: %s   [ ] or wheel: %s   Ctrl+S: save   F2: play", e.tool, selection)
+	g.drawText(screen, help, MapScreenMarginPixels, y, ColorHUDText)
+	g.drawText(screen, e.status, MapScreenMarginPixels, y+float64(MapScreenStatusHeightPixels)/Two, ColorHUDText)
+}
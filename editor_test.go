@@ -0,0 +1,224 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestEditor() *Editor {
+	e := NewEditor()
+	e.Open(NewMap())
+	return e
+}
+
+func TestEditor_PaintTile(t *testing.T) {
+	e := newTestEditor()
+	e.draft.Faces = map[TileCoord]FaceTextures{{X: 7, Y: 1}: {FaceWest: 2}}
+
+	if err := e.paintTile(3, 3, 2); err != nil {
+		t.Fatalf("paint: %v", err)
+	}
+	if tile, _ := e.draft.GetTileID(3, 3); tile != 2 {
+		t.Errorf("tile (3,3) = %d, want 2", tile)
+	}
+
+	// erasing a wall drops its face textures
+	if err := e.paintTile(7, 1, TileEmpty); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	if _, ok := e.draft.Faces[TileCoord{X: 7, Y: 1}]; ok {
+		t.Error("face textures of an erased wall were kept")
+	}
+
+	// the border can change texture but stays closed
+	if err := e.paintTile(0, 5, 3); err != nil {
+		t.Errorf("retexture border: %v", err)
+	}
+	if err := e.paintTile(0, 5, TileEmpty); err == nil {
+		t.Error("expected an error when erasing a border tile")
+	}
+
+	// the game map is not changed by the editor
+	if tile, _ := NewMap().GetTileID(3, 3); tile != TileEmpty {
+		t.Fatal("test expects (3,3) to be empty in the default map")
+	}
+}
+
+func TestEditor_DraftIsACopy(t *testing.T) {
+	m := NewMap()
+	e := NewEditor()
+	e.Open(m)
+
+	_ = e.paintTile(3, 3, 2)
+	e.draft.Sprites[0].Position = Vec2{X: 1.5, Y: 1.5}
+	e.draft.Rooms[0].W = 1
+	_ = e.setSpawn(PlayerSymbolX, Vec2{X: 1.5, Y: 1.5})
+
+	if tile, _ := m.GetTileID(3, 3); tile != TileEmpty {
+		t.Error("painting changed the original tiles")
+	}
+	if m.Sprites[0].Position == (Vec2{X: 1.5, Y: 1.5}) || m.Rooms[0].W == 1 || m.Spawn(PlayerSymbolX) == (Vec2{X: 1.5, Y: 1.5}) {
+		t.Error("editing changed the original map")
+	}
+}
+
+func TestEditor_SpriteAt(t *testing.T) {
+	e := newTestEditor()
+	e.draft.Sprites = []MapSprite{
+		{Type: "skull", Position: Vec2{X: 2, Y: 2}},
+		{Type: "light", Position: Vec2{X: 2.3, Y: 2}},
+	}
+
+	if i, ok := e.spriteAt(Vec2{X: 2.25, Y: 2}); !ok || i != 1 {
+		t.Errorf("spriteAt = %d, %v, want the closest sprite 1", i, ok)
+	}
+	if _, ok := e.spriteAt(Vec2{X: 4, Y: 4}); ok {
+		t.Error("no sprite should be picked far from every sprite")
+	}
+}
+
+func TestEditor_SetRoom(t *testing.T) {
+	e := newTestEditor()
+
+	// a new room of cell (0,0) replaces the old one and the rooms it overlaps
+	r := roomBetween(TileCoord{X: 9, Y: 3}, TileCoord{X: 1, Y: 1}, 0, 0)
+	if r != (Room{X: 1, Y: 1, W: 9, H: 3, Col: 0, Row: 0}) {
+		t.Fatalf("roomBetween = %+v", r)
+	}
+	e.setRoom(r)
+
+	if len(e.draft.Rooms) != GridSize*GridSize-1 {
+		t.Errorf("%d rooms, want the room (0,1) overlapped by the new one removed", len(e.draft.Rooms))
+	}
+	// the cell of the removed room cannot be claimed any more
+	if err := e.draft.Validate(); err == nil || !strings.Contains(err.Error(), "(0,1)") {
+		t.Errorf("Validate() = %v, want the cell (0,1) reported without a room", err)
+	}
+	if cx, cy, ok := e.draft.BoardCellAt(Vec2{X: 8.5, Y: 2.5}); !ok || cx != 0 || cy != 0 {
+		t.Errorf("BoardCellAt = %d, %d, %v, want the new room", cx, cy, ok)
+	}
+}
+
+func TestEditor_SetSpawn(t *testing.T) {
+	e := newTestEditor()
+
+	if err := e.setSpawn(PlayerSymbolO, Vec2{X: 7.5, Y: 1.5}); err == nil {
+		t.Error("expected an error for a spawn in a wall")
+	}
+	if err := e.setSpawn(PlayerSymbolO, Vec2{X: 3.5, Y: 3.5}); err != nil {
+		t.Fatalf("setSpawn: %v", err)
+	}
+	if got := e.draft.Spawn(PlayerSymbolO); got != (Vec2{X: 3.5, Y: 3.5}) {
+		t.Errorf("spawn of O = %v", got)
+	}
+}
+
+func TestEditorLayout(t *testing.T) {
+	m := NewMap()
+	originX, originY, tile := fullScreenMapLayout(m)
+
+	if originX+tile*float64(m.Width()) > WindowSizeX-MapScreenMarginPixels+1e-9 ||
+		originY+tile*float64(m.Height()) > WindowSizeY-MapScreenStatusHeightPixels {
+		t.Errorf("map of %.1f pixel tiles at (%.1f,%.1f) does not fit the screen", tile, originX, originY)
+	}
+
+	// the center of a tile on screen maps back to the tile
+	sx := int(originX + 3.5*tile)
+	sy := int(originY + 5.5*tile)
+	pos, ok := editorWorldPos(m, sx, sy)
+	if !ok || int(pos.X) != 3 || int(pos.Y) != 5 {
+		t.Errorf("editorWorldPos = %v, %v, want tile (3,5)", pos, ok)
+	}
+	if _, ok = editorWorldPos(m, 0, 0); ok {
+		t.Error("the screen corner should be outside of the map")
+	}
+}
+
+func TestEditorPalettes(t *testing.T) {
+	names := editorSpriteTypes(spriteTypes)
+	if slices.Contains(names, "poof") {
+		t.Error("one shot sprites should not be placeable")
+	}
+	if !slices.IsSorted(names) || !slices.Contains(names, "light") {
+		t.Errorf("sprite palette = %v", names)
+	}
+
+	tiles := editorWallTiles(TextureMap{1: {}, 3: {}, Light: {}})
+	if !slices.Equal(tiles, []TileID{1, 3}) {
+		t.Errorf("wall palette = %v, want the wall textures only", tiles)
+	}
+
+	if cycleIndex(2, 3, 1) != 0 || cycleIndex(0, 3, -1) != 2 || cycleIndex(-1, 3, 1) != 0 {
+		t.Error("cycleIndex does not wrap around")
+	}
+}
+
+func TestGame_CloseEditor(t *testing.T) {
+	g := &Game{
+		state:    StateEditor,
+		playerX:  NewPlayer(12.8, 12.8, PlayerSymbolX, "X"),
+		playerO:  NewPlayer(11.5, 11.5, PlayerSymbolO, "O"),
+		editor:   newTestEditor(),
+		worldMap: NewMap(),
+		mapPath:  filepath.Join(t.TempDir(), "dungeon.json"),
+	}
+	g.board[0][0][0] = PlayerSymbolX
+
+	// an invalid draft keeps the editor open
+	g.editor.draft.Spawns[PlayerSymbolX] = Vec2{X: 0.5, Y: 0.5}
+	g.toggleEditor()
+	if g.state != StateEditor {
+		t.Fatal("the editor closed on an invalid map")
+	}
+
+	// so does a board cell left without a room, it is reported when saving too
+	g.editor.draft.Spawns[PlayerSymbolX] = Vec2{X: 1.5, Y: 1.5}
+	rooms := g.editor.draft.Rooms
+	g.editor.draft.Rooms = rooms[1:]
+	for _, step := range []func(){g.toggleEditor, g.saveEditor} {
+		step()
+		if g.state != StateEditor || !strings.Contains(g.editor.status, "(0,0)") {
+			t.Errorf("state = %d, status %q, want the cell (0,0) reported", g.state, g.editor.status)
+		}
+	}
+	g.editor.draft.Rooms = rooms
+
+	_ = g.editor.setSpawn(PlayerSymbolX, Vec2{X: 1.5, Y: 1.5})
+	_ = g.editor.paintTile(3, 3, 2)
+	g.saveEditor()
+	g.playerX.dir = Vec2{X: 0, Y: 1}
+	g.toggleEditor()
+
+	if g.state != StatePlaying || g.board[0][0][0] != PlayerSymbolNone {
+		t.Errorf("state = %d, board = %v, want a new round", g.state, g.board)
+	}
+	if g.playerX.pos != (Vec2{X: 1.5, Y: 1.5}) || g.playerX.dir != defaultPlayerDir() {
+		t.Errorf("player X at %v looking %v, want the moved spawn", g.playerX.pos, g.playerX.dir)
+	}
+	if g.playerO.pos != g.worldMap.Spawn(PlayerSymbolO) {
+		t.Errorf("player O at %v, want its spawn", g.playerO.pos)
+	}
+	if tile, _ := g.worldMap.GetTileID(3, 3); tile != 2 {
+		t.Error("the edited map was not applied")
+	}
+	if len(g.sprites) != len(NewMap().Sprites) {
+		t.Errorf("%d sprites, want the decorations of the map", len(g.sprites))
+	}
+
+	saved, err := LoadMapFile(g.mapPath)
+	if err != nil {
+		t.Fatalf("load saved map: %v", err)
+	}
+	if tile, _ := saved.GetTileID(3, 3); tile != 2 || saved.Spawn(PlayerSymbolX) != (Vec2{X: 1.5, Y: 1.5}) {
+		t.Error("the saved map does not hold the edits")
+	}
+	if _, err = os.Stat(EditorDefaultMapPath); err == nil {
+		t.Error("the map was saved to the default path instead of the loaded map file")
+	}
+}
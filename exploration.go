@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "math"
+
+// Exploration is the set of map tiles a player has seen, indexed y*width+x.
+// Tiles are seen when a ray of the player's view passes through them or hits them.
+// The zero value has seen nothing, it is sized by the first Look or Reset.
+type Exploration struct {
+	width  int
+	height int
+	seen   []bool
+}
+
+// Reset forgets every seen tile and sizes the exploration for the map.
+func (e *Exploration) Reset(m Map) {
+	e.width, e.height = m.Width(), m.Height()
+	e.seen = make([]bool, e.width*e.height)
+}
+
+// Seen returns true if the tile (x, y) was seen, tiles outside of the map never are.
+func (e *Exploration) Seen(x, y int) bool {
+	if x < 0 || y < 0 || x >= e.width || y >= e.height {
+		return false
+	}
+	return e.seen[y*e.width+x]
+}
+
+// Mark records the tile (x, y) as seen.
+func (e *Exploration) Mark(x, y int) {
+	if x < 0 || y < 0 || x >= e.width || y >= e.height {
+		return
+	}
+	e.seen[y*e.width+x] = true
+}
+
+// Look casts ExplorationRays rays across the view of a camera at pos looking along dir
+// and marks the tiles they cross and the walls they hit.
+// A map of another size than the explored one (after SetMap) restarts the exploration.
+func (e *Exploration) Look(m Map, pos, dir Vec2, fovScale float64) {
+	if m.Width() != e.width || m.Height() != e.height {
+		e.Reset(m)
+	}
+
+	e.Mark(int(pos.X), int(pos.Y))
+	for i := range ExplorationRays {
+		cameraX := Two*float64(i)/float64(ExplorationRays-1) - 1
+		rayDir := GetRayDirection(dir, fovScale, cameraX)
+
+		hit := CastRay(pos, rayDir, m, m.MaxRayIterations())
+		if !hit.hit || math.IsInf(hit.distance, 1) {
+			continue
+		}
+
+		// the perpendicular distance scales the unnormalized ray direction to the hit point
+		e.markSegment(pos, pos.Add(rayDir.Scale(hit.distance)))
+		e.Mark(hit.cellX, hit.cellY)
+	}
+}
+
+// markSegment marks every tile crossed by the segment from a to b, walking the grid like the raycaster.
+func (e *Exploration) markSegment(a, b Vec2) {
+	x, y := int(math.Floor(a.X)), int(math.Floor(a.Y))
+	endX, endY := int(math.Floor(b.X)), int(math.Floor(b.Y))
+	d := b.Sub(a)
+
+	stepX, tMaxX, tDeltaX := segmentAxis(a.X, d.X)
+	stepY, tMaxY, tDeltaY := segmentAxis(a.Y, d.Y)
+
+	// a segment crosses at most one tile boundary per step and axis
+	for range abs(endX-x) + abs(endY-y) + 1 {
+		e.Mark(x, y)
+		if x == endX && y == endY {
+			return
+		}
+		if tMaxX < tMaxY {
+			x += stepX
+			tMaxX += tDeltaX
+		} else {
+			y += stepY
+			tMaxY += tDeltaY
+		}
+	}
+}
+
+// segmentAxis returns, for one axis of a segment starting at start with length delta,
+// the tile step direction, the fraction of the segment at the first tile boundary and between two boundaries.
+func segmentAxis(start, delta float64) (int, float64, float64) {
+	switch {
+	case delta > 0:
+		return 1, (math.Floor(start) + 1 - start) / delta, 1 / delta
+	case delta < 0:
+		return -1, (start - math.Floor(start)) / -delta, 1 / -delta
+	}
+	return 0, math.Inf(1), math.Inf(1)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestExploration_Look(t *testing.T) {
+	m := NewMap()
+	var e Exploration
+
+	// looking east along the corridor of row 3 from the first room
+	e.Look(m, Vec2{X: 1.5, Y: 3.5}, Vec2{X: 1, Y: 0}, GetK(PlayerFOV))
+
+	if !e.Seen(1, 3) {
+		t.Error("the player tile should be seen")
+	}
+	if !e.Seen(10, 3) {
+		t.Error("a tile crossed by the centre ray should be seen")
+	}
+	if !e.Seen(21, 3) {
+		t.Error("the wall hit at the end of the corridor should be seen")
+	}
+	if e.Seen(3, 10) || e.Seen(10, 17) {
+		t.Error("tiles of other rooms behind walls should not be seen")
+	}
+	if e.Seen(0, 1) {
+		t.Error("tiles behind the player should not be seen")
+	}
+}
+
+func TestExploration_MarkSegment(t *testing.T) {
+	var e Exploration
+	e.Reset(Map{Tiles: [][]TileID{make([]TileID, 5), make([]TileID, 5), make([]TileID, 5)}})
+
+	// a diagonal crosses only the tiles it passes through
+	e.markSegment(Vec2{X: 0.5, Y: 0.5}, Vec2{X: 2.5, Y: 2.2})
+	want := map[TileCoord]bool{{0, 0}: true, {1, 0}: true, {1, 1}: true, {2, 1}: true, {2, 2}: true}
+	for y := range 3 {
+		for x := range 5 {
+			if got := e.Seen(x, y); got != want[TileCoord{X: x, Y: y}] {
+				t.Errorf("tile (%d,%d) seen = %v, want %v", x, y, got, !got)
+			}
+		}
+	}
+}
+
+func TestExploration_ResetOnNewMapSize(t *testing.T) {
+	var e Exploration
+	e.Look(NewMap(), Vec2{X: 1.5, Y: 3.5}, Vec2{X: 1, Y: 0}, GetK(PlayerFOV))
+
+	small, err := ParseMap([]byte(`{"tiles": [[1,1,1,1],[1,0,0,1],[1,1,1,1]], ` + testBoardRooms() + `}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Look(small, Vec2{X: 1.5, Y: 1.5}, Vec2{X: -1, Y: 0}, GetK(PlayerFOV))
+
+	if e.Seen(10, 3) {
+		t.Error("tiles of the previous map are still seen")
+	}
+	if !e.Seen(0, 1) {
+		t.Error("the wall in front of the player should be seen")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "math"
+
+// Flyover is the camera showing the winning line during StateGameOver.
+// It visits the rooms of the line in order, circling the mark of each one for an equal share of the time,
+// the world is drawn from camera instead of the current player while it is active.
+// levels holds the level of the dungeon of each room, the camera goes up and down with the line of a cube.
+// The zero value is inactive, the camera is created by the first Start.
+type Flyover struct {
+	camera *Player
+	rooms  []Room
+	levels []int
+}
+
+// Start activates the flyover over the rooms of the given board cells and places the camera in the first one.
+// The levels of the dungeon share the layout of m, a room is found by column and row on any of them.
+func (f *Flyover) Start(m Map, line []BoardCell) {
+	if f.camera == nil {
+		f.camera = NewPlayer(0, 0, PlayerSymbolNone, "")
+	}
+
+	f.rooms = f.rooms[:0]
+	f.levels = f.levels[:0]
+	for _, c := range line {
+		f.rooms = append(f.rooms, m.BoardCellRoom(c.Col, c.Row))
+		f.levels = append(f.levels, c.Level)
+	}
+	f.Update(0)
+}
+
+// Stop deactivates the flyover, the world is drawn from the current player again.
+func (f *Flyover) Stop() {
+	f.rooms = f.rooms[:0]
+	f.levels = f.levels[:0]
+}
+
+// Camera returns the flyover camera, nil when the flyover is inactive.
+func (f *Flyover) Camera() *Player {
+	if len(f.rooms) == 0 {
+		return nil
+	}
+	return f.camera
+}
+
+// Update moves the camera to the given progress of the flyover, from 0 (start) to 1 (end).
+func (f *Flyover) Update(progress float64) {
+	if len(f.rooms) == 0 {
+		return
+	}
+
+	progress = math.Min(math.Max(progress, 0), 1)
+	i := min(int(progress*float64(len(f.rooms))), len(f.rooms)-1)
+	t := progress*float64(len(f.rooms)) - float64(i)
+
+	f.camera.pos, f.camera.dir = flyoverPose(f.rooms[i], t)
+	f.camera.level = f.levels[i]
+}
+
+// flyoverPose returns the camera position and direction circling the center of the room,
+// FlyoverSweep radians around it at t from 0 to 1, always looking at the center.
+func flyoverPose(r Room, t float64) (Vec2, Vec2) {
+	radius := float64(min(r.W, r.H)) * FlyoverRadiusScale
+	angle := math.Pi/Two + t*FlyoverSweep
+	offset := Vec2{X: math.Cos(angle), Y: math.Sin(angle)}
+
+	return r.Center().Add(offset.Scale(radius)), offset.Scale(-1)
+}
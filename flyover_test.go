@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlyoverPose_CirclesTheRoomCenter(t *testing.T) {
+	r := Room{X: 7, Y: 0, W: 7, H: 7, Col: 1, Row: 0}
+	for _, progress := range []float64{0, 0.25, 0.5, 1} {
+		pos, dir := flyoverPose(r, progress)
+		if !r.Contains(int(pos.X), int(pos.Y)) {
+			t.Errorf("t=%v: camera at %v is outside of the room", progress, pos)
+		}
+
+		toCenter := r.Center().Sub(pos).Normalize()
+		if math.Abs(toCenter.X-dir.X) > 1e-9 || math.Abs(toCenter.Y-dir.Y) > 1e-9 {
+			t.Errorf("t=%v: camera looks along %v, want the center %v", progress, dir, toCenter)
+		}
+	}
+}
+
+func TestFlyover_VisitsTheLineInOrder(t *testing.T) {
+	m := NewMap()
+	line := []BoardCell{{Col: 0, Row: 0}, {Col: 1, Row: 1}, {Col: 2, Row: 2}}
+
+	var f Flyover
+	if f.Camera() != nil {
+		t.Fatal("the zero flyover should be inactive")
+	}
+
+	f.Start(m, line)
+	for i, progress := range []float64{0.1, 0.5, 0.9} {
+		f.Update(progress)
+		pos := f.Camera().pos
+		if !m.BoardCellRoom(line[i].Col, line[i].Row).Contains(int(pos.X), int(pos.Y)) {
+			t.Errorf("progress %v: camera at %v, want it in the room of %v", progress, pos, line[i])
+		}
+		if !m.IsWalkable(pos) {
+			t.Errorf("progress %v: camera at %v is in a wall", progress, pos)
+		}
+	}
+
+	f.Stop()
+	if f.Camera() != nil {
+		t.Error("a stopped flyover should have no camera")
+	}
+}
+
+func TestGame_GameOverShowsTheWinningLine(t *testing.T) {
+	pX := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	pO := NewPlayer(2.5, 1.5, PlayerSymbolO, "O")
+	g := &Game{worldMap: NewMap(), playerX: pX, playerO: pO, currentPlayer: pX}
+
+	for col := range GridSize {
+		g.board[0][0][col] = PlayerSymbolX
+		g.sprites = append(g.sprites, &Sprite{
+			Position:  g.worldMap.BoardCellCenter(col, 0),
+			TextureID: PlayerXSymbol,
+		})
+	}
+	winner, line := g.board[0].CheckWinner()
+	g.handleGameEnd(RoundResult{Over: true, Winner: winner, Line: line})
+
+	if len(g.winLine) != GridSize || g.viewer() == pX {
+		t.Fatalf("winLine = %v, want the first row seen from the flyover camera", g.winLine)
+	}
+
+	g.stateTimer = GameOverDuration / Two
+	_ = g.updateGameOver()
+	for _, s := range g.sprites {
+		if s.Glow <= 0 {
+			t.Errorf("the mark at %v does not glow", s.Position)
+		}
+	}
+
+	g.stateTimer = DeltaTime / Two
+	_ = g.updateGameOver()
+	if g.winLine != nil || g.state != StateTurnTransition || g.viewer() != g.transition.Camera() {
+		t.Error("a new round should forget the winning line and move the camera to the next player")
+	}
+}
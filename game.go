@@ -4,8 +4,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -33,14 +38,36 @@ const (
 	StateNameInput GameState = iota
 	StatePlaying
 	StateGameOver
+	// StateEditor shows the map editor instead of the world, see Editor.
+	StateEditor
+	// StateTurnTransition moves the camera to the next player between two turns, see TurnTransition.
+	StateTurnTransition
 )
 
 type Game struct {
-	// state
+	// state, the board is a cube, modes playing on a flat board only use its first level
 	state  GameState
-	board  Board
+	board  Cube
 	winner *Player
 
+	// winLine is the cells of the winning line at game over, nil for a draw, the flyover shows them
+	winLine []BoardCell
+	flyover Flyover
+
+	// transition is the camera moving to the next player between two turns
+	transition TurnTransition
+
+	// mode is the rule set of the match, chosen on the setup screen
+	mode GameMode
+
+	// quantum holds the spooky marks of the quantum mode beside the board, and pickedCell the first room
+	// of the spooky mark the current player is placing, nil when none is picked
+	quantum    QuantumBoard
+	pickedCell *BoardCell
+
+	// timer is the time left to the current player, it runs in StatePlaying when the turn time limit is set
+	timer TurnTimer
+
 	// timer to handle game over transition
 	stateTimer float64
 
@@ -58,8 +85,29 @@ type Game struct {
 
 	// visuals
 	assets *Assets
+	world  *World
+
+	// sounds
+	audio *Audio
+
+	// developer tools
+	debug   *DebugOverlay
+	console *Console
+	editor  *Editor
+
+	// minimap, kept to toggle its overview
+	minimap *Minimap
 
+	// worldMap is the current map, mapPath its file when it was loaded with the console, empty otherwise
 	worldMap Map
+	mapPath  string
+
+	// levels are the maps of the levels of the dungeon bottom first, built from worldMap for the modes
+	// playing on several levels, nil otherwise
+	levels []Map
+
+	// user options, change them with ApplySettings
+	settings Settings
 
 	// loop lists
 	updatables []Updatable
@@ -67,29 +115,50 @@ type Game struct {
 }
 
 // NewGame creates a new Game instance with initialized assets and players.
-func NewGame() (*Game, error) {
-	assets, err := loadAssets()
+func NewGame(settings Settings) (*Game, error) {
+	assets, err := loadAssets(settings.AssetPack)
 	if err != nil {
 		return nil, err
 	}
 
-	spawnX := defaultPlayerXSpawn()
-	spawnO := defaultPlayerOSpawn()
+	sounds, err := NewAudio()
+	if err != nil {
+		return nil, fmt.Errorf("load audio: %w", err)
+	}
+
+	worldMap := NewMap()
+	spawnX := worldMap.Spawn(PlayerSymbolX)
+	spawnO := worldMap.Spawn(PlayerSymbolO)
 
 	pX := NewPlayer(spawnX.X, spawnX.Y, PlayerSymbolX, "X")
 	pO := NewPlayer(spawnO.X, spawnO.Y, PlayerSymbolO, "O")
 
 	minimap := &Minimap{}
 	hud := &Hud{}
-	world := &World{fovScale: GetK(PlayerFOV)}
+	world := &World{}
+	debug := &DebugOverlay{}
 
-	sprites := createSprites()
+	//nolint:gosec // seeds the random placements of the turn timer, not a secret
+	seed := rand.Uint64()
 
 	g := &Game{
 		state:          StateNameInput,
 		winner:         nil,
+		winLine:        nil,
+		flyover:        Flyover{camera: nil, rooms: nil, levels: nil},
+		transition:     TurnTransition{},
+		mode:           gameModes[0],
+		timer:          TurnTimer{Seed: seed},
 		assets:         assets,
-		worldMap:       NewMap(),
+		world:          world,
+		audio:          sounds,
+		debug:          debug,
+		console:        NewConsole(),
+		editor:         NewEditor(),
+		minimap:        minimap,
+		worldMap:       worldMap,
+		mapPath:        "",
+		levels:         nil,
 		playerX:        pX,
 		playerO:        pO,
 		currentPlayer:  pX,
@@ -97,23 +166,48 @@ func NewGame() (*Game, error) {
 		inputBuffer:    "",
 		updatables:     nil,
 		drawables:      nil,
-		sprites:        sprites,
+		sprites:        nil,
+	}
+
+	if errD := g.spawnDecorations(worldMap); errD != nil {
+		return nil, errD
 	}
 
+	// settings are validated on load, the error can be ignored here
+	_ = g.applySettings(settings)
+
 	g.updatables = append(g.updatables,
 		pX, pO,
+		&SpriteAnimator{},
+		sounds,
 	)
 
+	// dev builds reload the edited textures and map files
+	if reloader := newHotReloader(settings); reloader != nil {
+		g.updatables = append(g.updatables, reloader)
+	}
+
 	g.drawables = append(g.drawables,
 		world,
 		minimap,
 		hud,
+		debug,
 	)
 
 	return g, nil
 }
 
 func (g *Game) Update() error {
+	// ~: toggle the developer console, it grabs the keyboard while open, unless it is typed in a name
+	if inpututil.IsKeyJustPressed(ebiten.KeyGraveAccent) && !g.typingName() {
+		g.console.Toggle()
+		return nil
+	}
+	if g.console.IsOpen() {
+		g.console.Update(g)
+		return nil
+	}
+
 	for _, obj := range g.updatables {
 		obj.Update(g)
 	}
@@ -124,6 +218,46 @@ func (g *Game) Update() error {
 		return ebiten.Termination
 	}
 
+	// -/=: narrow or widen the field of view, unless they are typed in a name
+	if !g.typingName() {
+		if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+			g.adjustFOV(-SettingsFOVStep)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+			g.adjustFOV(SettingsFOVStep)
+		}
+	}
+
+	// F3: toggle the debug overlay
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		g.debug.Toggle()
+	}
+
+	// Tab: show or hide the overview map
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		g.minimap.ToggleOverview()
+	}
+
+	// L: show the next level of the dungeon on the minimap
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) && !g.typingName() {
+		g.minimap.CycleLevel(g, 1)
+	}
+
+	// F2: open the map editor, or play the edited map
+	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+		g.toggleEditor()
+	}
+
+	// F4: switch between the gpu and the software renderer
+	if inpututil.IsKeyJustPressed(ebiten.KeyF4) {
+		g.toggleRenderer()
+	}
+
+	// M: mute or unmute every sound
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) && !g.typingName() {
+		g.toggleMute()
+	}
+
 	// R: reset game state
 	if inpututil.IsKeyJustPressed(ebiten.KeyR) &&
 		(ebiten.IsKeyPressed(ebiten.KeyControlLeft) || ebiten.IsKeyPressed(ebiten.KeyControlRight)) {
@@ -138,11 +272,76 @@ func (g *Game) Update() error {
 		return g.updatePlaying()
 	case StateGameOver:
 		return g.updateGameOver()
+	case StateEditor:
+		return g.updateEditor()
+	case StateTurnTransition:
+		return g.updateTurnTransition()
 	}
 
 	return nil
 }
 
+// typingName returns true while the keyboard types a player name, the character shortcuts are off then.
+func (g *Game) typingName() bool {
+	return g.state == StateNameInput
+}
+
+// ApplySettings validates the settings, applies them to the running game and persists them.
+// Invalid fields are replaced by their defaults and reported in the returned error.
+func (g *Game) ApplySettings(s Settings) error {
+	errA := g.applySettings(s)
+	if errS := saveSettings(g.settings); errS != nil {
+		return errors.Join(errA, errS)
+	}
+	return errA
+}
+
+// applySettings validates the settings and recomputes the values derived from them.
+func (g *Game) applySettings(s Settings) error {
+	s, err := s.Normalized()
+	g.settings = s
+
+	g.world.fovScale = GetK(s.FOV)
+	g.world.ceilingColor = s.CeilingRGBA()
+	g.world.floorColor = s.FloorRGBA()
+	g.world.renderer = s.Renderer
+
+	g.playerX.applySettings(s)
+	g.playerO.applySettings(s)
+
+	return err
+}
+
+// adjustFOV changes the field of view by delta radians, clamped to the valid range.
+func (g *Game) adjustFOV(delta float64) {
+	s := g.settings
+	s.FOV = math.Min(math.Max(s.FOV+delta, SettingsMinFOV), SettingsMaxFOV)
+	if err := g.ApplySettings(s); err != nil {
+		slog.Warn("apply settings", "error", err)
+	}
+}
+
+// toggleMute mutes or unmutes the music and the sound effects.
+func (g *Game) toggleMute() {
+	s := g.settings
+	s.Muted = !s.Muted
+	if err := g.ApplySettings(s); err != nil {
+		slog.Warn("apply settings", "error", err)
+	}
+}
+
+// toggleRenderer switches the world between the gpu and the software renderer.
+func (g *Game) toggleRenderer() {
+	s := g.settings
+	s.Renderer = RendererSoftware
+	if g.settings.Renderer == RendererSoftware {
+		s.Renderer = RendererGPU
+	}
+	if err := g.ApplySettings(s); err != nil {
+		slog.Warn("apply settings", "error", err)
+	}
+}
+
 func (g *Game) updateNameInput() error {
 	chars := ebiten.AppendInputChars(nil)
 	for _, c := range chars {
@@ -159,6 +358,14 @@ func (g *Game) updateNameInput() error {
 		}
 	}
 
+	// Left/Right: choose the game mode of the match
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		g.mode = nextGameMode(g.gameMode(), -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		g.mode = nextGameMode(g.gameMode(), 1)
+	}
+
 	// Enter: confirm name
 	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
 		g.confirmName()
@@ -187,88 +394,474 @@ func (g *Game) confirmName() {
 		g.playerO.name = name
 		g.state = StatePlaying
 		g.inputBuffer = ""
+		g.timer.Start(g.settings.TurnTimeLimit)
+
+		// the mode is chosen, the dungeon gets its levels
+		if err := g.setLevels(); err != nil {
+			slog.Warn("set levels", "error", err)
+		}
 	}
 }
 
 func (g *Game) updatePlaying() error {
+	if g.timer.Tick(DeltaTime) {
+		return g.handleTurnTimeout()
+	}
+
+	// Q: choose the next mark, in modes offering several
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) {
+		g.cycleMark()
+	}
+
+	// F: take the teleporter to the level above, Shift+F below, in modes playing on several levels
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		step := 1
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			step = -1
+		}
+		g.takeTeleporter(g.currentPlayer, step)
+	}
+
+	// 1/2: choose the cell the cycle collapses into, in the quantum mode
+	choices := g.collapseChoices()
+	for i, key := range []ebiten.Key{ebiten.KeyDigit1, ebiten.KeyDigit2} {
+		if i < len(choices) && inpututil.IsKeyJustPressed(key) {
+			return g.playMove(choices[i])
+		}
+	}
+
 	if !inpututil.IsKeyJustPressed(ebiten.KeyE) {
 		return nil
 	}
 
-	// compute the board cell from the current player world position
-	pos := g.currentPlayer.pos
-	cx := int(pos.X / MapRoomStride)
-	cy := int(pos.Y / MapRoomStride)
+	// compute the board cell from the current player world position and level
+	p := g.currentPlayer
+	cx, cy, ok := g.levelMap(p.level).BoardCellAt(p.pos)
+	if !ok {
+		return nil
+	}
 
-	// check if within board bounds section
-	if cx < 0 || cx >= GridSize || cy < 0 || cy >= GridSize {
+	// the mode decides if the cell can be claimed
+	move, ok := g.moveAt(BoardCell{Col: cx, Row: cy, Level: p.level})
+	if !ok {
 		return nil
 	}
 
-	// cell must be empty
-	if g.board[cy][cx] != PlayerSymbolNone {
+	return g.playMove(move)
+}
+
+// playMove applies a legal move of the current player with the rules of the mode, spawns its marks
+// and ends the round or passes the turn to the next player.
+// A collapse does not end the turn, the player goes on with their own mark and their time starts again.
+func (g *Game) playMove(m Move) error {
+	mode := g.gameMode()
+	before := g.board[0]
+	if err := mode.Apply(&g.board, m); err != nil {
+		return err
+	}
+
+	switch m.Kind {
+	case MovePlace:
+		g.spawnMark(m.Cell, m.Mark)
+	case MoveSpooky:
+		g.spawnQuantumMarks()
+		g.poofAt(m.Cell)
+		g.poofAt(m.Pair)
+	case MoveCollapse:
+		g.spawnQuantumMarks()
+		for _, c := range collapsedCells(before, g.board[0]) {
+			g.poofAt(c)
+		}
+	}
+
+	if result := mode.Result(&g.board, m); result.Over {
+		g.handleGameEnd(result)
 		return nil
 	}
 
+	if m.Kind == MoveCollapse {
+		g.timer.Start(g.settings.TurnTimeLimit)
+		return nil
+	}
+
+	g.passTurn()
+	return nil
+}
+
+// moveAt returns the move the current player makes pressing E in the room of the cell, ok is false when there is none.
+// A spooky mark takes two rooms: the first press picks the room and makes no move, the second one makes the move
+// with both rooms, pressing again in the picked room puts it back.
+func (g *Game) moveAt(cell BoardCell) (Move, bool) {
+	p := g.currentPlayer
+	moves := g.gameMode().LegalMoves(&g.board, p.symbol)
+	for _, m := range moves {
+		if m.Kind != MoveSpooky && m.Cell == cell && m.Mark == g.currentMark() {
+			g.pickedCell = nil
+			return m, true
+		}
+	}
+
+	if g.pickedCell == nil {
+		spooky := func(m Move) bool { return m.Kind == MoveSpooky && (m.Cell == cell || m.Pair == cell) }
+		if slices.ContainsFunc(moves, spooky) {
+			g.pickedCell = &cell
+		}
+		return Move{}, false
+	}
+
+	first := *g.pickedCell
+	g.pickedCell = nil
+	m := spookyMove(p.symbol, first, cell)
+	return m, slices.Contains(moves, m)
+}
+
+// currentMark returns the mark the current player draws next: their choice when the mode offers it,
+// the first mark of the mode otherwise.
+func (g *Game) currentMark() PlayerSymbol {
+	marks := g.gameMode().Marks(g.currentPlayer.symbol)
+	if len(marks) == 0 || slices.Contains(marks, g.currentPlayer.mark) {
+		return g.currentPlayer.mark
+	}
+	return marks[0]
+}
+
+// cycleMark makes the current player choose the next mark the mode offers.
+func (g *Game) cycleMark() {
+	marks := g.gameMode().Marks(g.currentPlayer.symbol)
+	i := slices.Index(marks, g.currentMark())
+	g.currentPlayer.mark = marks[cycleIndex(i, len(marks), 1)]
+}
+
+// gameMode returns the rules of the match, the classic ones when none was chosen.
+// The quantum mode plays on the quantum board of the game.
+func (g *Game) gameMode() GameMode {
+	switch mode := g.mode.(type) {
+	case nil:
+		return gameModes[0]
+	case QuantumMode:
+		mode.board = &g.quantum
+		return mode
+	default:
+		return mode
+	}
+}
+
+// passTurn gives the turn to the other player, the camera moves to them.
+func (g *Game) passTurn() {
+	g.pickedCell = nil
+	previous := g.currentPlayer
+	g.switchPlayer()
+	g.startTurnTransition(previous)
+}
+
+// handleTurnTimeout applies the timeout setting when the current player runs out of time.
+func (g *Game) handleTurnTimeout() error {
+	switch g.settings.TurnTimeout {
+	case TurnTimeoutSkip:
+	case TurnTimeoutRandom:
+		if m, ok := g.timer.RandomMove(g.gameMode().LegalMoves(&g.board, g.currentPlayer.symbol)); ok {
+			return g.playMove(m)
+		}
+	case TurnTimeoutForfeit:
+		winner := g.gameMode().NextPlayer(g.currentPlayer.symbol)
+		g.handleGameEnd(RoundResult{Over: true, Winner: winner, Line: nil, Second: PlayerSymbolNone})
+		return nil
+	}
+
+	g.passTurn()
+	return nil
+}
+
+// PlaceMark puts the symbol on the empty board cell (column cx, row cy) of the ground level,
+// without checking the rules of the mode, and spawns the matching mark sprite in the center of the room.
+func (g *Game) PlaceMark(cx, cy int, symbol PlayerSymbol) error {
+	if cx < 0 || cx >= GridSize || cy < 0 || cy >= GridSize {
+		return fmt.Errorf("cell (%d,%d) is outside of the board", cy, cx)
+	}
+	if symbol == PlayerSymbolNone {
+		return errors.New("cannot place an empty symbol")
+	}
+	if g.board[0][cy][cx] != PlayerSymbolNone {
+		return fmt.Errorf("cell (%d,%d) is already taken", cy, cx)
+	}
+
 	// update the board (this is the authoritative game state)
-	g.board[cy][cx] = g.currentPlayer.symbol
+	g.board[0][cy][cx] = symbol
+	g.spawnMark(BoardCell{Col: cx, Row: cy, Level: 0}, symbol)
+	return nil
+}
 
+// spawnMark spawns the mark sprite of the symbol in the room of the board cell, on its level,
+// with the subscript of the quantum mode.
+func (g *Game) spawnMark(cell BoardCell, symbol PlayerSymbol) {
 	// spawn a visual mark sprite at the center of the cell
 	// this avoids jitter when the player is not perfectly centered in the room
-	cellCenter := Vec2{
-		X: (float64(cx) + HalfTile) * MapRoomStride,
-		Y: (float64(cy) + HalfTile) * MapRoomStride,
-	}
+	cellCenter := g.levelMap(cell.Level).BoardCellCenter(cell.Col, cell.Row)
 
+	// the poof comes after the mark so it is drawn over it
 	g.sprites = append(g.sprites, &Sprite{
 		Position:  cellCenter,
-		TextureID: g.currentPlayer.symbolTextureID,
+		TextureID: symbol.MarkTextureID(),
 		Scale:     1.0,
 		Z:         0.0,
 		Hidden:    false,
+		Level:     cell.Level,
+		Subscript: g.markSubscript(cell),
 	})
+	g.poofAt(cell)
+}
 
-	winnerSym := g.board.CheckWinner()
-	gameOver := winnerSym != PlayerSymbolNone || g.board.IsFull()
-	if gameOver {
-		g.handleGameEnd(winnerSym)
-		return nil
+// poofAt shows a puff of smoke in the room of the board cell, over the marks there, with the sound of a placed mark.
+func (g *Game) poofAt(cell BoardCell) {
+	cellCenter := g.levelMap(cell.Level).BoardCellCenter(cell.Col, cell.Row)
+	g.spawnPoof(cellCenter, cell.Level)
+	g.audio.PlayAt(g, SoundPlace, cellCenter)
+}
+
+// spawnPoof adds a puff of smoke at pos on the level, the one of the asset pack when it replaces it.
+func (g *Game) spawnPoof(pos Vec2, level int) {
+	poof := g.SpriteTypes()["poof"].NewSprite(pos)
+	poof.Level = level
+	g.sprites = append(g.sprites, poof)
+}
+
+// ClearCell empties the board cell (column cx, row cy) of the ground level and removes its mark sprite.
+func (g *Game) ClearCell(cx, cy int) error {
+	if cx < 0 || cx >= GridSize || cy < 0 || cy >= GridSize {
+		return fmt.Errorf("cell (%d,%d) is outside of the board", cy, cx)
 	}
 
-	g.switchPlayer()
+	g.board[0][cy][cx] = PlayerSymbolNone
+
+	cellCenter := g.worldMap.BoardCellCenter(cx, cy)
+	filtered := g.sprites[:0]
+	for _, s := range g.sprites {
+		if s.Position == cellCenter && s.Level == 0 && isMarkTexture(s.TextureID) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	g.sprites = filtered
+
+	return nil
+}
+
+// Teleport moves the player to pos on its level, which must be walkable unless noclip is on.
+// With noclip the position only has to be inside the map.
+func (g *Game) Teleport(p *Player, pos Vec2) error {
+	if !p.canMoveTo(g, pos) {
+		if p.noclip {
+			return fmt.Errorf("position (%.2f,%.2f) is outside of the map", pos.X, pos.Y)
+		}
+		return fmt.Errorf("position (%.2f,%.2f) is not walkable", pos.X, pos.Y)
+	}
+	p.pos = pos
+	return nil
+}
+
+// SpriteTypes returns the decoration types that can be spawned, with the ones of the asset pack.
+// Without loaded assets only the built-in types are available.
+func (g *Game) SpriteTypes() map[string]SpriteType {
+	if g == nil || g.assets == nil || g.assets.SpriteTypes == nil {
+		return spriteTypes
+	}
+	return g.assets.SpriteTypes
+}
+
+// SpawnSprite adds a decoration sprite of the named type at pos.
+func (g *Game) SpawnSprite(name string, pos Vec2) error {
+	t, ok := g.SpriteTypes()[name]
+	if !ok {
+		return fmt.Errorf("unknown sprite type %q", name)
+	}
+	g.sprites = append(g.sprites, t.NewSprite(pos))
+	return nil
+}
+
+// SetMap replaces the world map, the ground level of the dungeon, and rebuilds the levels above it.
+// Players start again on the spawns of the map, looking in the default direction.
+// The decorations are replaced by the sprites of the new map. Placed marks stay, callers start a new round
+// when the rooms of the new map are elsewhere.
+func (g *Game) SetMap(m Map) error {
+	return g.replaceMap(m, func(p *Player) Vec2 {
+		p.dir = defaultPlayerDir()
+		return m.Spawn(p.symbol)
+	})
+}
+
+// ReloadMap replaces the world map by a new version of the same map, like SetMap.
+// Players keep their position when it is still walkable, otherwise they are moved to the closest walkable tile.
+func (g *Game) ReloadMap(m Map) error {
+	return g.replaceMap(m, func(p *Player) Vec2 {
+		return p.pos
+	})
+}
+
+// replaceMap validates the map and makes it the world map. Each player is moved to the walkable tile
+// closest to the position returned by place, so a spawn left in a wall by a map without spawns is still usable.
+func (g *Game) replaceMap(m Map, place func(p *Player) Vec2) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	for _, p := range []*Player{g.playerX, g.playerO} {
+		pos, ok := m.NearestWalkable(place(p))
+		if !ok {
+			return errors.New("map has no walkable tile")
+		}
+		p.pos = pos
+		p.explored.Reset(m)
+	}
+
+	g.worldMap = m
+	return g.setLevels()
+}
+
+// spawnDecorations replaces the decoration sprites by the ones of the map and the teleporter pads of the levels,
+// the board marks are kept. lanterns crackle where they hang.
+func (g *Game) spawnDecorations(m Map) error {
+	marks := g.sprites[:0]
+	for _, s := range g.sprites {
+		if isMarkTexture(s.TextureID) {
+			marks = append(marks, s)
+		}
+	}
+	g.sprites = append(marks, m.NewSprites(g.SpriteTypes())...)
+	g.sprites = append(g.sprites, g.teleporterSprites()...)
+
+	if g.audio == nil {
+		return nil
+	}
+	g.audio.ClearEmitters()
+	for _, s := range g.sprites {
+		if s.TextureID != Light {
+			continue
+		}
+		if err := g.audio.AddEmitter(SoundCrackle, s.Position); err != nil {
+			return fmt.Errorf("add lantern sound: %w", err)
+		}
+	}
 	return nil
 }
 
+// isMarkTexture returns true if the texture is one of the board marks.
+func isMarkTexture(id TextureID) bool {
+	return id == PlayerXSymbol || id == PlayerOSymbol
+}
+
+// boardCellAt returns the board cell (column, row) of the room containing the world position.
+// ok is false when the position is outside of the board section of the map.
+func boardCellAt(pos Vec2) (int, int, bool) {
+	cx := int(pos.X / MapRoomStride)
+	cy := int(pos.Y / MapRoomStride)
+
+	// check if within board bounds section
+	if pos.X < 0 || pos.Y < 0 || cx >= GridSize || cy >= GridSize {
+		return 0, 0, false
+	}
+	return cx, cy, true
+}
+
 func (g *Game) updateGameOver() error {
 	g.stateTimer -= DeltaTime
+
+	elapsed := GameOverDuration - g.stateTimer
+	g.flyover.Update(elapsed / GameOverDuration)
+	g.glowWinningMarks(WinGlowStrength * (1 - math.Cos(elapsed*WinGlowPulseSpeed)) / Two)
+
 	if g.stateTimer <= 0 {
+		// the next round starts with a transition from the flyover, or the last player on a draw
+		from := *g.viewer()
 		g.switchPlayer()
 		g.resetBoard()
+		g.startTurnTransition(&from)
 	}
 	return nil
 }
 
-func (g *Game) switchPlayer() {
-	if g.currentPlayer.symbol == PlayerSymbolX {
-		g.currentPlayer = g.playerO
-	} else {
-		g.currentPlayer = g.playerX
+// startTurnTransition moves the camera from the view of the player from to the current player.
+func (g *Game) startTurnTransition(from *Player) {
+	g.timer.Stop()
+	g.state = StateTurnTransition
+	g.stateTimer = TurnTransitionDuration
+	g.transition.Start(from, g.currentPlayer)
+}
+
+func (g *Game) updateTurnTransition() error {
+	g.stateTimer -= DeltaTime
+	g.transition.Update(1 - g.stateTimer/TurnTransitionDuration)
+
+	if g.stateTimer <= 0 {
+		g.transition.Stop()
+		g.state = StatePlaying
+		g.timer.Start(g.settings.TurnTimeLimit)
 	}
+	return nil
 }
 
-func (g *Game) handleGameEnd(w PlayerSymbol) {
-	g.state = StateGameOver
-	g.stateTimer = GameOverDuration
+// switchPlayer gives the turn to the player the mode picks after the current one.
+func (g *Game) switchPlayer() {
+	if next := g.player(g.gameMode().NextPlayer(g.currentPlayer.symbol)); next != nil {
+		g.currentPlayer = next
+	}
+}
 
-	switch w {
+// player returns the player of the symbol, nil for PlayerSymbolNone.
+func (g *Game) player(symbol PlayerSymbol) *Player {
+	switch symbol {
 	case PlayerSymbolX:
-		g.winner = g.playerX
-		g.playerX.score++
+		return g.playerX
 	case PlayerSymbolO:
-		g.winner = g.playerO
-		g.playerO.score++
+		return g.playerO
 	case PlayerSymbolNone:
-		g.winner = nil
+	}
+	return nil
+}
+
+// glowWinningMarks sets the glow of the mark sprites of the winning line.
+func (g *Game) glowWinningMarks(glow float64) {
+	for _, c := range g.winLine {
+		center := g.levelMap(c.Level).BoardCellCenter(c.Col, c.Row)
+		for _, s := range g.sprites {
+			if s.Position == center && s.Level == c.Level && isMarkTexture(s.TextureID) {
+				s.Glow = glow
+			}
+		}
+	}
+}
+
+// viewer returns the camera the world is drawn from: the flyover at game over,
+// the transition between two turns and the current player otherwise.
+func (g *Game) viewer() *Player {
+	if camera := g.flyover.Camera(); camera != nil {
+		return camera
+	}
+	if camera := g.transition.Camera(); camera != nil {
+		return camera
+	}
+	return g.currentPlayer
+}
+
+// handleGameEnd ends the round with the result, the players score what the mode gives them
+// and the flyover shows the line of the result when there is one.
+func (g *Game) handleGameEnd(r RoundResult) {
+	g.state = StateGameOver
+	g.stateTimer = GameOverDuration
+	g.timer.Stop()
+
+	g.winLine = r.Line
+	if r.Line != nil {
+		g.flyover.Start(g.worldMap, r.Line)
+	}
+
+	g.winner = g.player(r.Winner)
+	g.playerX.score += g.gameMode().Score(r, PlayerSymbolX)
+	g.playerO.score += g.gameMode().Score(r, PlayerSymbolO)
+
+	if g.winner != nil {
+		g.audio.Play(g, SoundWin)
+	} else {
+		g.audio.Play(g, SoundDraw)
 	}
 }
 
@@ -283,7 +876,14 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	case StateGameOver:
 		g.drawPlaying(screen)
 		g.drawGameOver(screen)
+	case StateEditor:
+		g.editor.Draw(screen, g)
+	case StateTurnTransition:
+		g.drawPlaying(screen)
+		g.drawTurnBanner(screen)
 	}
+
+	g.console.Draw(screen, g)
 }
 
 func (g *Game) Layout(_, _ int) (int, int) {
@@ -301,6 +901,9 @@ func (g *Game) drawNameInput(screen *ebiten.Image) {
 
 	info := "Type name, Enter = OK, Backspace = delete"
 	g.drawText(screen, info, NameInputX, NameInputY+NameInputLineHeight*2, color.White)
+
+	mode := "Mode: " + g.gameMode().Name() + "  (Left/Right to change)"
+	g.drawText(screen, mode, NameInputX, NameInputY+NameInputLineHeight*NameInputModeLine, color.White)
 }
 
 func (g *Game) drawPlaying(screen *ebiten.Image) {
@@ -376,15 +979,42 @@ func (g *Game) drawGameOver(screen *ebiten.Image) {
 	)
 }
 
+// drawTurnBanner announces the player whose turn starts, fading out with the transition.
+func (g *Game) drawTurnBanner(screen *ebiten.Image) {
+	alpha := float32(math.Min(g.stateTimer/TurnTransitionDuration*Two, 1))
+	msg := fmt.Sprintf("Player %s's turn", g.currentPlayer.symbol)
+
+	vector.FillRect(screen, 0, float32(WindowSizeYDiv2-TurnBannerHeight/Two), float32(WindowSizeX),
+		TurnBannerHeight, scaleAlpha(ColorGameOverBackground, alpha), false)
+	g.drawBigText(screen, msg, float64(WindowSizeXDiv2), float64(WindowSizeYDiv2), Center,
+		scaleAlpha(ColorGameOverText, alpha))
+}
+
+// scaleAlpha returns the premultiplied color c faded by alpha, from 0 (transparent) to 1 (unchanged).
+func scaleAlpha(c color.RGBA, alpha float32) color.RGBA {
+	return color.RGBA{
+		R: uint8(float32(c.R) * alpha),
+		G: uint8(float32(c.G) * alpha),
+		B: uint8(float32(c.B) * alpha),
+		A: uint8(float32(c.A) * alpha),
+	}
+}
+
 func (g *Game) resetBoard() {
 	g.board.Reset()
+	g.quantum = QuantumBoard{}
+	g.pickedCell = nil
 	g.winner = nil
+	g.winLine = nil
+	g.flyover.Stop()
+	g.transition.Stop()
+	g.timer.Start(g.settings.TurnTimeLimit)
 	g.state = StatePlaying
 
 	// remove mark sprites (keeping decorations like lights)
 	filtered := g.sprites[:0]
 	for _, s := range g.sprites {
-		if s.TextureID != PlayerXSymbol && s.TextureID != PlayerOSymbol {
+		if !isMarkTexture(s.TextureID) {
 			filtered = append(filtered, s)
 		}
 	}
@@ -397,8 +1027,13 @@ func (g *Game) fullReset() {
 
 	g.playerX.score = 0
 	g.playerO.score = 0
+	g.playerX.explored.Reset(g.worldMap)
+	g.playerO.explored.Reset(g.worldMap)
+	g.playerX.level = 0
+	g.playerO.level = 0
 
 	g.state = StateNameInput
+	g.timer.Stop()
 	g.editingPlayerX = true
 	g.inputBuffer = ""
 
@@ -0,0 +1,309 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MoveKind is what a move does on the board.
+type MoveKind int
+
+const (
+	// MovePlace draws a mark on the cell.
+	MovePlace MoveKind = iota
+	// MoveSpooky draws a spooky mark on the cell and on the pair cell at once, in the quantum mode.
+	MoveSpooky
+	// MoveCollapse collapses the marks of a cycle, the mark closing it into the cell, in the quantum mode.
+	MoveCollapse
+)
+
+// Move is a mark placed on a cell of the board.
+// Player is the symbol of the player making the move and Mark the symbol drawn on the board,
+// they are the same unless the mode lets players choose their mark.
+// Kind is what the move does, a mark on a single cell unless the mode says otherwise,
+// and Pair the second cell of a spooky mark.
+type Move struct {
+	Kind   MoveKind
+	Cell   BoardCell
+	Pair   BoardCell
+	Player PlayerSymbol
+	Mark   PlayerSymbol
+}
+
+// RoundResult is the state of a round after a move.
+// Over is true when the round ended, Winner is the symbol of the winning player, PlayerSymbolNone for a draw,
+// and Line the cells to highlight, nil when there are none.
+// Second is the player who filled a line too, after the winner, PlayerSymbolNone in most modes.
+type RoundResult struct {
+	Over   bool
+	Winner PlayerSymbol
+	Line   []BoardCell
+	Second PlayerSymbol
+}
+
+// GameMode is a rule set of the game: which moves are legal, how they change the board,
+// when a round ends and who won it, who plays next and how a round is scored.
+// The game loop only talks to the board through its mode, so variants do not touch the loop.
+// The board is a cube, modes playing on a flat board only use its first level.
+type GameMode interface {
+	// Name is shown on the setup screen.
+	Name() string
+	// Levels returns the number of levels of the board, each one is a level of the dungeon.
+	Levels() int
+	// Marks returns the symbols the player may draw on the board, the first one is drawn unless they choose.
+	Marks(player PlayerSymbol) []PlayerSymbol
+	// LegalMoves returns the moves the player can make on the board, none when the round is over.
+	LegalMoves(b *Cube, player PlayerSymbol) []Move
+	// Apply plays a legal move on the board, it returns an error and leaves the board unchanged otherwise.
+	Apply(b *Cube, m Move) error
+	// Result returns the state of the round after the move last was applied to the board.
+	Result(b *Cube, last Move) RoundResult
+	// NextPlayer returns the symbol of the player playing after current.
+	NextPlayer(current PlayerSymbol) PlayerSymbol
+	// Score returns the points the player scores for a finished round.
+	Score(r RoundResult, player PlayerSymbol) float64
+}
+
+var errIllegalMove = errors.New("illegal move")
+
+// gameModes are the modes offered on the setup screen, the first one is the default.
+//
+//nolint:gochecknoglobals // registry of the game modes
+var gameModes = []GameMode{
+	ClassicMode{},
+	MisereMode{},
+	WildMode{},
+	CubeMode{},
+	QuantumMode{},
+}
+
+// nextGameMode returns the mode step places after current in gameModes, wrapping around.
+func nextGameMode(current GameMode, step int) GameMode {
+	i := slices.IndexFunc(gameModes, func(m GameMode) bool { return m.Name() == current.Name() })
+	return gameModes[cycleIndex(i, len(gameModes), step)]
+}
+
+// isLegalMove returns true if the mode allows the move on the board.
+func isLegalMove(mode GameMode, b *Cube, m Move) bool {
+	return slices.Contains(mode.LegalMoves(b, m.Player), m)
+}
+
+// applyLegalMove draws the mark of the move on its cell if the mode allows it.
+func applyLegalMove(mode GameMode, b *Cube, m Move) error {
+	if !isLegalMove(mode, b, m) {
+		return fmt.Errorf("%w: %s on cell (%d,%d,%d)", errIllegalMove, m.Mark, m.Cell.Level, m.Cell.Row, m.Cell.Col)
+	}
+	b[m.Cell.Level][m.Cell.Row][m.Cell.Col] = m.Mark
+	return nil
+}
+
+// emptyCellMoves returns a move of the player for every mark on every empty cell of the first levels of the board.
+func emptyCellMoves(b *Cube, levels int, player PlayerSymbol, marks []PlayerSymbol) []Move {
+	if player == PlayerSymbolNone {
+		return nil
+	}
+
+	var moves []Move
+	for level := range min(levels, GridSize) {
+		for cy, row := range b[level] {
+			for cx, symbol := range row {
+				if symbol != PlayerSymbolNone {
+					continue
+				}
+				for _, mark := range marks {
+					cell := BoardCell{Col: cx, Row: cy, Level: level}
+					move := Move{Kind: MovePlace, Cell: cell, Pair: BoardCell{}, Player: player, Mark: mark}
+					moves = append(moves, move)
+				}
+			}
+		}
+	}
+	return moves
+}
+
+// flatMoves returns a move of the player for every mark on every empty cell of the flat board,
+// none once a line is filled.
+func flatMoves(b *Cube, player PlayerSymbol, marks []PlayerSymbol) []Move {
+	if winner, _ := b[0].CheckWinner(); winner != PlayerSymbolNone {
+		return nil
+	}
+	return emptyCellMoves(b, 1, player, marks)
+}
+
+// forkingMode is implemented by the modes keeping state of their own beside the board,
+// fork returns the mode playing on a copy of that state, so moves can be tried out without changing the game.
+type forkingMode interface {
+	fork() GameMode
+}
+
+// winningCells returns the cells where a move of the player wins the round right away, each cell once.
+func winningCells(mode GameMode, b *Cube, player PlayerSymbol) []BoardCell {
+	var cells []BoardCell
+	for _, m := range mode.LegalMoves(b, player) {
+		try := mode
+		if f, ok := mode.(forkingMode); ok {
+			try = f.fork()
+		}
+
+		next := *b
+		if try.Apply(&next, m) != nil || slices.Contains(cells, m.Cell) {
+			continue
+		}
+		if r := try.Result(&next, m); r.Over && r.Winner == player {
+			cells = append(cells, m.Cell)
+		}
+	}
+	return cells
+}
+
+// ClassicMode is the classic tic-tac-toe: players draw their own symbol on empty cells in turn,
+// the first to fill a line wins a point and a full board is a draw.
+type ClassicMode struct{}
+
+// Name returns "Classic".
+func (ClassicMode) Name() string {
+	return "Classic"
+}
+
+// Levels returns 1, the flat board.
+func (ClassicMode) Levels() int {
+	return 1
+}
+
+// Marks returns the player's own symbol.
+func (ClassicMode) Marks(player PlayerSymbol) []PlayerSymbol {
+	return []PlayerSymbol{player}
+}
+
+// LegalMoves returns a move with the player's symbol on every empty cell.
+func (c ClassicMode) LegalMoves(b *Cube, player PlayerSymbol) []Move {
+	return flatMoves(b, player, c.Marks(player))
+}
+
+// Apply draws the mark of the move on its cell.
+func (c ClassicMode) Apply(b *Cube, m Move) error {
+	return applyLegalMove(c, b, m)
+}
+
+// Result returns the owner of a filled line as the winner, or a draw when the board is full.
+func (ClassicMode) Result(b *Cube, _ Move) RoundResult {
+	winner, line := b[0].CheckWinner()
+	return RoundResult{
+		Over:   winner != PlayerSymbolNone || b[0].IsFull(),
+		Winner: winner,
+		Line:   line,
+		Second: PlayerSymbolNone,
+	}
+}
+
+// NextPlayer alternates X and O.
+func (ClassicMode) NextPlayer(current PlayerSymbol) PlayerSymbol {
+	return current.Opponent()
+}
+
+// Score gives a point to the winner.
+func (ClassicMode) Score(r RoundResult, player PlayerSymbol) float64 {
+	if r.Over && r.Winner == player && player != PlayerSymbolNone {
+		return 1
+	}
+	return 0
+}
+
+// MisereMode is the misère tic-tac-toe: the classic moves, but the player who fills a line of their symbol
+// loses the round and the other one scores.
+type MisereMode struct {
+	ClassicMode
+}
+
+// Name returns "Misère".
+func (MisereMode) Name() string {
+	return "Misère"
+}
+
+// Result returns the opponent of the owner of a filled line as the winner, or a draw when the board is full.
+func (m MisereMode) Result(b *Cube, last Move) RoundResult {
+	r := m.ClassicMode.Result(b, last)
+	if r.Winner != PlayerSymbolNone {
+		r.Winner = m.NextPlayer(r.Winner)
+	}
+	return r
+}
+
+// WildMode is the wild tic-tac-toe: players draw either symbol, chosen before each move,
+// and the player who fills a line, of any symbol, wins the round.
+type WildMode struct {
+	ClassicMode
+}
+
+// Name returns "Wild".
+func (WildMode) Name() string {
+	return "Wild"
+}
+
+// Marks returns both symbols, the player's own first.
+func (WildMode) Marks(player PlayerSymbol) []PlayerSymbol {
+	return []PlayerSymbol{player, player.Opponent()}
+}
+
+// LegalMoves returns a move with each symbol on every empty cell.
+func (w WildMode) LegalMoves(b *Cube, player PlayerSymbol) []Move {
+	return flatMoves(b, player, w.Marks(player))
+}
+
+// Apply draws the chosen mark of the move on its cell.
+func (w WildMode) Apply(b *Cube, m Move) error {
+	return applyLegalMove(w, b, m)
+}
+
+// Result returns the player of the last move as the winner when it filled a line, or a draw when the board is full.
+func (w WildMode) Result(b *Cube, last Move) RoundResult {
+	r := w.ClassicMode.Result(b, last)
+	if r.Winner != PlayerSymbolNone {
+		r.Winner = last.Player
+	}
+	return r
+}
+
+// CubeMode is the 3×3×3 tic-tac-toe: the classic moves on the cells of three stacked levels,
+// the first to fill one of the 49 lines of the cube wins a point and a full cube is a draw.
+type CubeMode struct {
+	ClassicMode
+}
+
+// Name returns "Cube".
+func (CubeMode) Name() string {
+	return "Cube"
+}
+
+// Levels returns GridSize, the cube has as many levels as cells on a side.
+func (CubeMode) Levels() int {
+	return GridSize
+}
+
+// LegalMoves returns a move with the player's symbol on every empty cell of the cube.
+func (c CubeMode) LegalMoves(b *Cube, player PlayerSymbol) []Move {
+	if winner, _ := b.CheckWinner(); winner != PlayerSymbolNone {
+		return nil
+	}
+	return emptyCellMoves(b, c.Levels(), player, c.Marks(player))
+}
+
+// Apply draws the mark of the move on its cell.
+func (c CubeMode) Apply(b *Cube, m Move) error {
+	return applyLegalMove(c, b, m)
+}
+
+// Result returns the owner of a filled line of the cube as the winner, or a draw when the cube is full.
+func (CubeMode) Result(b *Cube, _ Move) RoundResult {
+	winner, line := b.CheckWinner()
+	return RoundResult{
+		Over:   winner != PlayerSymbolNone || b.IsFull(),
+		Winner: winner,
+		Line:   line,
+		Second: PlayerSymbolNone,
+	}
+}
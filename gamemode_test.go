@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassicMode_LegalMovesAndApply(t *testing.T) {
+	mode := ClassicMode{}
+	b := Cube{{
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolNone, PlayerSymbolNone, PlayerSymbolNone},
+		{PlayerSymbolNone, PlayerSymbolNone, PlayerSymbolNone},
+	}}
+
+	if got := mode.LegalMoves(&b, PlayerSymbolX); len(got) != 7 {
+		t.Errorf("LegalMoves() = %d moves, want one per empty cell", len(got))
+	}
+
+	taken := Move{Cell: BoardCell{Col: 1, Row: 0}, Player: PlayerSymbolX, Mark: PlayerSymbolX}
+	if err := mode.Apply(&b, taken); !errors.Is(err, errIllegalMove) || b[0][0][1] != PlayerSymbolO {
+		t.Errorf("Apply() on a taken cell = %v, board = %v", err, b)
+	}
+
+	other := Move{Cell: BoardCell{Col: 2, Row: 0}, Player: PlayerSymbolX, Mark: PlayerSymbolO}
+	if err := mode.Apply(&b, other); !errors.Is(err, errIllegalMove) {
+		t.Errorf("Apply() with the other symbol = %v, want an illegal move", err)
+	}
+
+	m := Move{Cell: BoardCell{Col: 2, Row: 0}, Player: PlayerSymbolX, Mark: PlayerSymbolX}
+	if err := mode.Apply(&b, m); err != nil || b[0][0][2] != PlayerSymbolX {
+		t.Errorf("Apply() = %v, board = %v", err, b)
+	}
+}
+
+func TestClassicMode_ResultAndScore(t *testing.T) {
+	mode := ClassicMode{}
+	b := Cube{{
+		{PlayerSymbolO, PlayerSymbolO, PlayerSymbolO},
+		{PlayerSymbolX, PlayerSymbolX, PlayerSymbolNone},
+		{PlayerSymbolX, PlayerSymbolNone, PlayerSymbolNone},
+	}}
+
+	r := mode.Result(&b, Move{Cell: BoardCell{Col: 2, Row: 0}, Player: PlayerSymbolO, Mark: PlayerSymbolO})
+	if !r.Over || r.Winner != PlayerSymbolO || len(r.Line) != GridSize {
+		t.Fatalf("Result() = %+v, want O to win with the first row", r)
+	}
+	if mode.Score(r, PlayerSymbolO) != 1 || mode.Score(r, PlayerSymbolX) != 0 {
+		t.Error("the winner scores one point, the loser none")
+	}
+	if len(mode.LegalMoves(&b, PlayerSymbolX)) != 0 {
+		t.Error("no move is legal once the round is won")
+	}
+
+	draw := Cube{{
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolX},
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolO},
+		{PlayerSymbolO, PlayerSymbolX, PlayerSymbolX},
+	}}
+	r = mode.Result(&draw, Move{})
+	if !r.Over || r.Winner != PlayerSymbolNone || mode.Score(r, PlayerSymbolX) != 0 {
+		t.Errorf("Result() of a full board = %+v, want a draw", r)
+	}
+
+	if mode.NextPlayer(PlayerSymbolX) != PlayerSymbolO || mode.NextPlayer(PlayerSymbolO) != PlayerSymbolX {
+		t.Error("classic players alternate")
+	}
+}
+
+func TestNextGameMode_Wraps(t *testing.T) {
+	first := gameModes[0]
+	if got := nextGameMode(first, len(gameModes)); got.Name() != first.Name() {
+		t.Errorf("a full cycle from %s ended on %s", first.Name(), got.Name())
+	}
+	if got := nextGameMode(first, -1); got.Name() != gameModes[len(gameModes)-1].Name() {
+		t.Errorf("the mode before the first should be the last, got %s", got.Name())
+	}
+}
+
+func TestMisereMode_CompletingALineLoses(t *testing.T) {
+	mode := MisereMode{}
+	b := Cube{{
+		{PlayerSymbolX, PlayerSymbolX, PlayerSymbolNone},
+		{PlayerSymbolO, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolNone, PlayerSymbolNone, PlayerSymbolNone},
+	}}
+
+	m := Move{Cell: BoardCell{Col: 2, Row: 0}, Player: PlayerSymbolX, Mark: PlayerSymbolX}
+	if err := mode.Apply(&b, m); err != nil {
+		t.Fatal(err)
+	}
+	r := mode.Result(&b, m)
+	if !r.Over || r.Winner != PlayerSymbolO || len(r.Line) != GridSize {
+		t.Fatalf("Result() = %+v, want O to win when X fills a row", r)
+	}
+	if mode.Score(r, PlayerSymbolO) != 1 || mode.Score(r, PlayerSymbolX) != 0 {
+		t.Error("the player who did not fill the line scores")
+	}
+}
+
+func TestWildMode_EitherMarkAndTheMoverWins(t *testing.T) {
+	mode := WildMode{}
+	b := Cube{{
+		{PlayerSymbolO, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolX, PlayerSymbolNone, PlayerSymbolNone},
+		{PlayerSymbolNone, PlayerSymbolNone, PlayerSymbolX},
+	}}
+
+	if got := mode.LegalMoves(&b, PlayerSymbolX); len(got) != 2*5 {
+		t.Errorf("LegalMoves() = %d moves, want both marks on the 5 empty cells", len(got))
+	}
+
+	// X fills the row of O with an O and wins
+	m := Move{Cell: BoardCell{Col: 2, Row: 0}, Player: PlayerSymbolX, Mark: PlayerSymbolO}
+	if err := mode.Apply(&b, m); err != nil || b[0][0][2] != PlayerSymbolO {
+		t.Fatalf("Apply() = %v, board = %v", err, b)
+	}
+	if r := mode.Result(&b, m); !r.Over || r.Winner != PlayerSymbolX {
+		t.Errorf("Result() = %+v, want X to win with the row of O", r)
+	}
+}
+
+func TestWinningCells(t *testing.T) {
+	b := Cube{{
+		{PlayerSymbolX, PlayerSymbolNone, PlayerSymbolX},
+		{PlayerSymbolNone, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolX, PlayerSymbolNone, PlayerSymbolO},
+	}}
+
+	// (1,0) completes the first row, (0,1) the first column
+	want := []BoardCell{{Col: 1, Row: 0}, {Col: 0, Row: 1}}
+	got := winningCells(ClassicMode{}, &b, PlayerSymbolX)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("classic winningCells(X) = %v, want %v", got, want)
+	}
+	if got = winningCells(ClassicMode{}, &b, PlayerSymbolO); len(got) != 0 {
+		t.Errorf("classic winningCells(O) = %v, want none", got)
+	}
+
+	// completing a line loses in misère
+	if got = winningCells(MisereMode{}, &b, PlayerSymbolX); len(got) != 0 {
+		t.Errorf("misère winningCells(X) = %v, want none", got)
+	}
+
+	// in wild O may fill the lines of X too
+	if got = winningCells(WildMode{}, &b, PlayerSymbolO); len(got) != len(want) {
+		t.Errorf("wild winningCells(O) = %v, want %v", got, want)
+	}
+}
+
+func TestGame_CycleMark(t *testing.T) {
+	g := &Game{currentPlayer: NewPlayer(0, 0, PlayerSymbolX, "X"), mode: WildMode{}}
+	if g.currentMark() != PlayerSymbolX {
+		t.Fatalf("currentMark() = %v, want the player's own symbol first", g.currentMark())
+	}
+
+	g.cycleMark()
+	if g.currentMark() != PlayerSymbolO {
+		t.Errorf("currentMark() = %v after a switch, want O", g.currentMark())
+	}
+
+	// a mode without the choice ignores it
+	g.mode = ClassicMode{}
+	if g.currentMark() != PlayerSymbolX {
+		t.Errorf("classic currentMark() = %v, want X", g.currentMark())
+	}
+	g.cycleMark()
+	if g.currentMark() != PlayerSymbolX {
+		t.Errorf("classic currentMark() = %v after a switch, want X", g.currentMark())
+	}
+}
+
+func TestCubeMode(t *testing.T) {
+	mode := CubeMode{}
+	var b Cube
+
+	if mode.Levels() != GridSize || (ClassicMode{}).Levels() != 1 {
+		t.Fatal("the cube has a level per cell on a side, the classic board one")
+	}
+	if got := mode.LegalMoves(&b, PlayerSymbolX); len(got) != GridSize*GridSize*GridSize {
+		t.Errorf("LegalMoves() = %d moves, want one per cell of the cube", len(got))
+	}
+	if got := (ClassicMode{}).LegalMoves(&b, PlayerSymbolX); len(got) != GridSize*GridSize {
+		t.Errorf("classic LegalMoves() = %d moves, want the cells of the first level only", len(got))
+	}
+
+	// X climbs the column in the center of the cube
+	var last Move
+	for level := range GridSize {
+		last = Move{Cell: BoardCell{Col: 1, Row: 1, Level: level}, Player: PlayerSymbolX, Mark: PlayerSymbolX}
+		if err := mode.Apply(&b, last); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := mode.Result(&b, last)
+	if !r.Over || r.Winner != PlayerSymbolX || len(r.Line) != GridSize || r.Line[GridSize-1].Level != GridSize-1 {
+		t.Fatalf("Result() = %+v, want X to win with the column through the levels", r)
+	}
+	if len(mode.LegalMoves(&b, PlayerSymbolO)) != 0 {
+		t.Error("no move is legal once the round is won")
+	}
+
+	// the classic rules only look at the first level
+	if r = (ClassicMode{}).Result(&b, last); r.Over {
+		t.Errorf("classic Result() = %+v, want the round to go on", r)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build gpu
+
+package main
+
+import (
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// go test -tags gpu -run TestGoldenGPU needs a display, xvfb-run works on a headless machine.
+// the pixels of the gpu renderer can only be read back while the game runs,
+// so the tests run inside the first update of a game.
+
+// goldenGPUMaxDiffRatio is the share of pixels allowed to differ beyond goldenTolerance.
+// the gpu filters the scaled strips, texel edges may land on the next pixel.
+const goldenGPUMaxDiffRatio = 0.01
+
+// testGame runs the tests in its first update and stops the game.
+type testGame struct {
+	m    *testing.M
+	code int
+}
+
+func (g *testGame) Update() error {
+	g.code = g.m.Run()
+	return ebiten.Termination
+}
+
+func (g *testGame) Draw(*ebiten.Image) {}
+
+func (g *testGame) Layout(int, int) (int, int) {
+	return WindowSizeX, WindowSizeY
+}
+
+func TestMain(m *testing.M) {
+	g := &testGame{m: m, code: 0}
+	if err := ebiten.RunGame(g); err != nil && !errors.Is(err, ebiten.Termination) {
+		panic(err)
+	}
+	os.Exit(g.code)
+}
+
+// TestGoldenGPU draws every golden camera with the gpu renderer and compares its pixels to the software renderer.
+// on failure both frames and a diff image are written to goldenFailureDir.
+func TestGoldenGPU(t *testing.T) {
+	textures, err := LoadTextures()
+	if err != nil {
+		t.Fatalf("load textures: %v", err)
+	}
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := goldenGame(textures, tc)
+
+			sw := &World{fovScale: GetK(PlayerFOV), ceilingColor: ColorCeiling, floorColor: ColorFloor,
+				renderer: RendererSoftware}
+			sw.Draw(ebiten.NewImage(WindowSizeX, WindowSizeY), g)
+			want := sw.software.Image()
+
+			screen := ebiten.NewImage(WindowSizeX, WindowSizeY)
+			gpu := &World{fovScale: GetK(PlayerFOV), ceilingColor: ColorCeiling, floorColor: ColorFloor,
+				renderer: RendererGPU}
+			gpu.Draw(screen, g)
+
+			got := image.NewRGBA(image.Rect(0, 0, WindowSizeX, WindowSizeY))
+			screen.ReadPixels(got.Pix)
+
+			diff, count := diffImages(want, got, goldenTolerance)
+			if float64(count) <= goldenGPUMaxDiffRatio*WindowSizeX*WindowSizeY {
+				return
+			}
+
+			writePNG(t, filepath.Join(goldenFailureDir, tc.name+".gpu.png"), got)
+			writePNG(t, filepath.Join(goldenFailureDir, tc.name+".software.png"), want)
+			writePNG(t, filepath.Join(goldenFailureDir, tc.name+".gpu.diff.png"), diff)
+			t.Errorf("%d pixels of the gpu frame differ from the software renderer, see %s", count, goldenFailureDir)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// go test -run TestGolden -update rewrites the golden images from the current renderer.
+//
+//nolint:gochecknoglobals // test flag
+var updateGolden = flag.Bool("update", false, "rewrite the golden images")
+
+//nolint:gochecknoglobals // color of the differing pixels in diff images
+var colorGoldenDiff = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+const (
+	goldenDir        = "testdata/golden"
+	goldenFailureDir = "testdata/golden/failures"
+	goldenWidth      = 320
+	goldenHeight     = 180
+
+	// goldenTolerance is the largest channel difference accepted per pixel, for float rounding
+	goldenTolerance = 2
+)
+
+// goldenCase is a fixed camera on the default map, angle in degrees.
+type goldenCase struct {
+	name  string
+	pos   Vec2
+	angle float64
+}
+
+// goldenCases are fixed cameras on the default map with the default sprites.
+// each one targets a part of the renderer: texture strips, face shading, sprite clipping and occlusion.
+//
+//nolint:gochecknoglobals // test table shared by the golden tests
+var goldenCases = []goldenCase{
+	{name: "spawn_west", pos: Vec2{X: 12.8, Y: 12.8}, angle: 180},
+	{name: "corridor_east", pos: Vec2{X: 1.5, Y: 3.5}, angle: 0},
+	{name: "corner_faces", pos: Vec2{X: 5.5, Y: 5.5}, angle: 315},
+	{name: "light_on_gopher_wall", pos: Vec2{X: 11.5, Y: 3.5}, angle: 270},
+	{name: "light_on_wall", pos: Vec2{X: 10.5, Y: 4.5}, angle: 250},
+	{name: "skull_through_doorway", pos: Vec2{X: 12.6, Y: 10.5}, angle: 262},
+	{name: "sprite_close_clipped", pos: Vec2{X: 1.6, Y: 4.5}, angle: 10},
+	{name: "skull_and_chains", pos: Vec2{X: 4.5, Y: 1.5}, angle: 45},
+}
+
+// TestGolden renders every golden camera with the software renderer and compares it to its committed png.
+// on failure the actual frame and a diff image (differing pixels in red) are written to goldenFailureDir.
+func TestGolden(t *testing.T) {
+	textures, err := LoadTexturePixels()
+	if err != nil {
+		t.Fatalf("load textures: %v", err)
+	}
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := renderOptions{
+				MapPath:  "",
+				PackPath: "",
+				Pos:      tc.pos,
+				Angle:    tc.angle,
+				FOV:      PlayerFOV,
+				Width:    goldenWidth,
+				Height:   goldenHeight,
+				Sprites:  true,
+				Out:      "",
+			}
+
+			r, errR := renderFrame(NewMap(), textures, spriteTypes, opts)
+			if errR != nil {
+				t.Fatalf("render: %v", errR)
+			}
+			got := r.Image()
+
+			goldenPath := filepath.Join(goldenDir, tc.name+".png")
+			if *updateGolden {
+				writePNG(t, goldenPath, got)
+				return
+			}
+
+			want, errG := readPNG(goldenPath)
+			if errG != nil {
+				t.Fatalf("read golden image (run with -update to create it): %v", errG)
+			}
+
+			diff, count := diffImages(want, got, goldenTolerance)
+			if count == 0 {
+				return
+			}
+
+			writePNG(t, filepath.Join(goldenFailureDir, tc.name+".actual.png"), got)
+			writePNG(t, filepath.Join(goldenFailureDir, tc.name+".diff.png"), diff)
+			t.Errorf("%d pixels differ from %s, see %s", count, goldenPath, goldenFailureDir)
+		})
+	}
+}
+
+// TestGolden_GPUMatchesSoftware draws every golden camera with both renderers through World.Draw.
+// the gpu path must cast the same rays, fill the same depth and wall rows, pick the same wall strips
+// and show the same sprites as the software renderer checked by the goldens.
+// the pixels of both paths are compared by the gpu tagged tests, which need a display.
+func TestGolden_GPUMatchesSoftware(t *testing.T) {
+	textures, err := LoadTextures()
+	if err != nil {
+		t.Fatalf("load textures: %v", err)
+	}
+	screen := ebiten.NewImage(WindowSizeX, WindowSizeY)
+
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := goldenGame(textures, tc)
+
+			gpu := &World{fovScale: GetK(PlayerFOV), renderer: RendererGPU}
+			gpu.Draw(screen, g)
+			gpuSprites := g.debug.stats.visibleSprites
+
+			sw := &World{fovScale: GetK(PlayerFOV), renderer: RendererSoftware}
+			sw.Draw(screen, g)
+			if g.debug.stats.visibleSprites != gpuSprites {
+				t.Errorf("visible sprites: gpu %d, software %d", gpuSprites, g.debug.stats.visibleSprites)
+			}
+
+			p := g.currentPlayer
+			for x := range WindowSizeX {
+				if gpu.zBuffer[x] != sw.software.zBuffer[x] || gpu.wallRows[x] != sw.software.wallRows[x] {
+					t.Fatalf("column %d: gpu depth %v rows %v, software depth %v rows %v", x,
+						gpu.zBuffer[x], gpu.wallRows[x], sw.software.zBuffer[x], sw.software.wallRows[x])
+				}
+
+				hit := sw.software.Hit(x)
+				if !hit.hit {
+					continue
+				}
+				strip, _ := gpu.resolveTextureStripFromHit(g, p, hit)
+				id, _ := g.worldMap.WallTexture(hit.cellX, hit.cellY, hit.face)
+				if want := textures[id].strip(0, textures[id].frameColumn(0, wallTextureU(hit))); strip != want {
+					t.Fatalf("column %d: the gpu draws another wall strip than the software renderer", x)
+				}
+			}
+		})
+	}
+}
+
+// goldenGame returns a game on the default map with its sprites, seen by a player at the golden camera.
+// the debug overlay is enabled so the renderers report their visible sprites.
+func goldenGame(textures TextureMap, tc goldenCase) *Game {
+	m := NewMap()
+	rad := tc.angle * math.Pi / 180
+
+	p := NewPlayer(tc.pos.X, tc.pos.Y, PlayerSymbolX, "X")
+	p.dir = Vec2{X: math.Cos(rad), Y: math.Sin(rad)}
+	return &Game{
+		playerX:       p,
+		currentPlayer: p,
+		sprites:       m.NewSprites(spriteTypes),
+		assets:        &Assets{NormalTextFace: nil, BigTextFace: nil, Textures: textures},
+		worldMap:      m,
+		debug:         &DebugOverlay{enabled: true},
+	}
+}
+
+func TestDiffImages(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	b := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	a.SetRGBA(0, 0, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+	b.SetRGBA(0, 0, color.RGBA{R: 101, G: 100, B: 100, A: 255})
+	b.SetRGBA(1, 0, color.RGBA{R: 0, G: 0, B: 50, A: 255})
+
+	diff, count := diffImages(a, b, 1)
+	if count != 1 {
+		t.Fatalf("count = %d, want 1", count)
+	}
+	if got := diff.RGBAAt(1, 0); got != colorGoldenDiff {
+		t.Errorf("differing pixel = %v, want %v", got, colorGoldenDiff)
+	}
+
+	// size mismatch counts every pixel
+	if _, n := diffImages(a, image.NewRGBA(image.Rect(0, 0, 1, 1)), 1); n != 2 {
+		t.Errorf("size mismatch count = %d, want 2", n)
+	}
+}
+
+// diffImages compares two images channel by channel.
+// it returns an image with the differing pixels in red over a darkened want, and the number of such pixels.
+// images of different sizes differ on every pixel of want.
+func diffImages(want image.Image, got *image.RGBA, tolerance int) (*image.RGBA, int) {
+	bounds := want.Bounds()
+	diff := image.NewRGBA(bounds)
+
+	if got.Bounds() != bounds {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				diff.SetRGBA(x, y, colorGoldenDiff)
+			}
+		}
+		return diff, bounds.Dx() * bounds.Dy()
+	}
+
+	count := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			w, _ := color.RGBAModel.Convert(want.At(x, y)).(color.RGBA)
+			g := got.RGBAAt(x, y)
+
+			if channelDiff(w.R, g.R) > tolerance || channelDiff(w.G, g.G) > tolerance ||
+				channelDiff(w.B, g.B) > tolerance || channelDiff(w.A, g.A) > tolerance {
+				diff.SetRGBA(x, y, colorGoldenDiff)
+				count++
+				continue
+			}
+
+			// keep the frame readable but dim so the red pixels stand out
+			diff.SetRGBA(x, y, color.RGBA{R: w.R / 4, G: w.G / 4, B: w.B / 4, A: 255})
+		}
+	}
+	return diff, count
+}
+
+func channelDiff(a, b uint8) int {
+	return max(int(a), int(b)) - min(int(a), int(b))
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %q: %w", path, err)
+	}
+	return img, nil
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create %q: %v", filepath.Dir(path), err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %q: %v", path, err)
+	}
+	defer f.Close()
+
+	if errE := png.Encode(f, img); errE != nil {
+		t.Fatalf("encode %q: %v", path, errE)
+	}
+}
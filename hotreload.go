@@ -0,0 +1,11 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !dev || js
+
+package main
+
+// newHotReloader returns nil, hot reloading is only part of dev builds (go build -tags dev).
+func newHotReloader(_ Settings) Updatable {
+	return nil
+}
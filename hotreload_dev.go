@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build dev && !js
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HotReloadInterval is the time between two checks of the watched files, in seconds.
+const HotReloadInterval = 0.5
+
+// HotReloader watches the asset pack directory and the map file loaded with the console.
+// Edited textures are decoded and uploaded again, an edited map replaces the current one and
+// players stay in place when their position is still walkable.
+// Files are polled from the game loop, so reloading never races with drawing.
+type HotReloader struct {
+	packDir string
+	elapsed float64
+
+	// modification times of the pack files and of the map file seen at the last check
+	packStamps map[string]time.Time
+	mapPath    string
+	mapStamp   time.Time
+}
+
+// newHotReloader watches the asset pack of the settings when it is a directory.
+// The map file is picked up when a map is loaded with the console.
+func newHotReloader(s Settings) Updatable {
+	r := &HotReloader{
+		packDir:    "",
+		elapsed:    0,
+		packStamps: nil,
+		mapPath:    "",
+		mapStamp:   time.Time{},
+	}
+
+	if info, err := os.Stat(s.AssetPack); err == nil && info.IsDir() {
+		r.packDir = s.AssetPack
+		r.packStamps = fileStamps(r.packDir)
+	}
+	return r
+}
+
+func (r *HotReloader) Update(g *Game) {
+	r.elapsed += DeltaTime
+	if r.elapsed < HotReloadInterval {
+		return
+	}
+	r.elapsed = 0
+
+	r.checkMap(g)
+	r.checkPack(g)
+}
+
+// checkMap reloads the map file when it changed since the last check.
+func (r *HotReloader) checkMap(g *Game) {
+	if g.mapPath == "" {
+		return
+	}
+
+	stamp := fileStamp(g.mapPath)
+	if g.mapPath != r.mapPath {
+		// a new map was just loaded from the console
+		r.mapPath, r.mapStamp = g.mapPath, stamp
+		return
+	}
+	if stamp.Equal(r.mapStamp) {
+		return
+	}
+	r.mapStamp = stamp
+
+	m, err := LoadMapFile(r.mapPath)
+	if err == nil {
+		err = g.ReloadMap(m)
+	}
+	if err != nil {
+		reportReload(g, fmt.Sprintf("reload %s: %v", r.mapPath, err))
+		return
+	}
+	reportReload(g, fmt.Sprintf("reloaded %s", r.mapPath))
+}
+
+// checkPack reloads the textures and sprite types when a file of the pack directory changed.
+func (r *HotReloader) checkPack(g *Game) {
+	if r.packDir == "" {
+		return
+	}
+
+	stamps := fileStamps(r.packDir)
+	if maps.Equal(stamps, r.packStamps) {
+		return
+	}
+	r.packStamps = stamps
+
+	pack, errP := LoadAssetPack(r.packDir)
+	if errP != nil {
+		reportReload(g, fmt.Sprintf("reload %s: %v", r.packDir, errP))
+
+		// a manifest saved halfway must not swap the textures back to the built-in ones,
+		// otherwise the valid entries are kept like at startup
+		if errors.Is(errP, errAssetPackUnreadable) {
+			return
+		}
+	}
+
+	textures, types, err := loadPackTextures(pack)
+	if err != nil {
+		reportReload(g, fmt.Sprintf("reload %s: %v", r.packDir, err))
+		return
+	}
+
+	g.assets.Textures = textures
+	g.assets.SpriteTypes = types
+	g.assets.PackName = pack.Name
+	reportReload(g, fmt.Sprintf("reloaded %d textures from %s", len(pack.Textures), r.packDir))
+}
+
+// reportReload logs a reload message and prints it in the console.
+func reportReload(g *Game, msg string) {
+	slog.Info("hot reload", "message", msg)
+	if g.console != nil {
+		g.console.print(msg)
+	}
+}
+
+// fileStamps returns the modification time of every file under dir.
+func fileStamps(dir string) map[string]time.Time {
+	stamps := make(map[string]time.Time)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil //nolint:nilerr // unreadable entries are skipped, they are retried on the next check
+		}
+		stamps[path] = fileStamp(path)
+		return nil
+	})
+	return stamps
+}
+
+// fileStamp returns the modification time of the file, zero if it cannot be read.
+func fileStamp(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build dev && !js
+
+package main
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTouched writes the file with a modification time in the future, so every write is a change.
+func writeTouched(t *testing.T, path string, data []byte, age time.Duration) {
+	t.Helper()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Now().Add(age)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkNow runs the reloader as if HotReloadInterval had elapsed.
+func checkNow(r *HotReloader, g *Game) {
+	r.elapsed = HotReloadInterval
+	r.Update(g)
+}
+
+func TestHotReloader_Map(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m.json")
+	writeTouched(t, path, []byte(`{"tiles": [[1,1,1,1],[1,0,0,1],[1,1,1,1]], `+testBoardRooms()+`}`), 0)
+
+	m, err := LoadMapFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Game{
+		playerX:  NewPlayer(1.5, 1.5, PlayerSymbolX, "X"),
+		playerO:  NewPlayer(2.5, 1.5, PlayerSymbolO, "O"),
+		console:  NewConsole(),
+		worldMap: m,
+		mapPath:  path,
+	}
+
+	r, _ := newHotReloader(DefaultSettings()).(*HotReloader)
+	checkNow(r, g)
+
+	// the room grows, both players stay where they are, then X's tile becomes a wall
+	grown := `{"tiles": [[1,1,1,1,1],[1,0,0,0,1],[1,1,1,1,1]], ` + testBoardRooms() + `}`
+	writeTouched(t, path, []byte(grown), time.Second)
+	checkNow(r, g)
+	if g.worldMap.Width() != 5 {
+		t.Fatalf("map width = %d, want the reloaded map", g.worldMap.Width())
+	}
+	if g.playerX.pos != (Vec2{X: 1.5, Y: 1.5}) || g.playerO.pos != (Vec2{X: 2.5, Y: 1.5}) {
+		t.Errorf("players moved on a walkable map: %v %v", g.playerX.pos, g.playerO.pos)
+	}
+
+	walled := `{"tiles": [[1,1,1,1,1],[1,1,0,0,1],[1,1,1,1,1]], ` + testBoardRooms() + `}`
+	writeTouched(t, path, []byte(walled), 2*time.Second)
+	checkNow(r, g)
+	if !g.worldMap.IsWalkable(g.playerX.pos) {
+		t.Errorf("player X at %v is inside a wall", g.playerX.pos)
+	}
+
+	// a broken map keeps the current one
+	writeTouched(t, path, []byte(`{"tiles": [[0]]}`), 3*time.Second)
+	checkNow(r, g)
+	if g.worldMap.Width() != 5 {
+		t.Error("a broken map file replaced the map")
+	}
+}
+
+func TestHotReloader_Textures(t *testing.T) {
+	dir := t.TempDir()
+	writeTouched(t, filepath.Join(dir, AssetPackManifestName), []byte(`{"walls": [{"tile": 1, "file": "w.png"}]}`), 0)
+	writeTouched(t, filepath.Join(dir, "w.png"), solidPNG(t, 16, 16, color.RGBA{R: 255, A: 255}), 0)
+
+	s := DefaultSettings()
+	s.AssetPack = dir
+	textures, types, err := loadPackTextures(loadAssetPackOrWarn(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Game{console: NewConsole(), assets: &Assets{Textures: textures, SpriteTypes: types}}
+
+	r, _ := newHotReloader(s).(*HotReloader)
+	checkNow(r, g)
+	if g.assets.Textures[WallBrick].Size != 16 {
+		t.Fatalf("wall size = %d, want the pack texture", g.assets.Textures[WallBrick].Size)
+	}
+
+	// the wall is redrawn at a higher resolution
+	writeTouched(t, filepath.Join(dir, "w.png"), solidPNG(t, 128, 128, color.RGBA{G: 255, A: 255}), time.Second)
+	checkNow(r, g)
+	if g.assets.Textures[WallBrick].Size != 128 {
+		t.Errorf("wall size = %d after the edit, want 128", g.assets.Textures[WallBrick].Size)
+	}
+	if g.assets.Textures[Chains].Pixels == nil {
+		t.Error("the embedded textures are lost after a reload")
+	}
+
+	// a manifest saved halfway keeps the textures of the pack
+	writeTouched(t, filepath.Join(dir, AssetPackManifestName), []byte(`{"walls": [{"tile": 1,`), 2*time.Second)
+	checkNow(r, g)
+	if size := g.assets.Textures[WallBrick].Size; size != 128 {
+		t.Errorf("wall size = %d after a broken manifest, want the pack texture kept", size)
+	}
+}
+
+func TestHotReloader_WaitsForInterval(t *testing.T) {
+	r := &HotReloader{}
+	g := &Game{mapPath: filepath.Join(t.TempDir(), "m.json")}
+
+	r.Update(g)
+	if r.mapPath != "" {
+		t.Error("files were checked before the interval elapsed")
+	}
+}
@@ -4,8 +4,11 @@
 package main
 
 import (
+	"image/color"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -80,6 +83,197 @@ func drawImageContained(destination *ebiten.Image, image *ebiten.Image, x, y, wi
 	destination.DrawImage(image, options)
 }
 
+// turnCountdownText returns the whole seconds left to the current player, rounded up so that 0 means the end.
+func turnCountdownText(remaining float64) string {
+	return "Time: " + strconv.Itoa(int(math.Ceil(remaining)))
+}
+
+// formatScore returns the score with its half point when there is one, "3" or "2.5".
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+// drawTurnCountdown draws the time left to the current player right aligned at x, in red for the last seconds.
+func drawTurnCountdown(screen *ebiten.Image, g *Game, x, y float64) {
+	col := ColorHUDText
+	if g.timer.Remaining <= HudTurnTimerWarningSeconds {
+		col = ColorHUDTimerWarning
+	}
+	g.drawTextWithFace(screen, turnCountdownText(g.timer.Remaining), x, y, TopRight, col,
+		g.assets.NormalTextFace, TextLineSpacing)
+}
+
+// hudBoardCell returns the top left corner and the size in pixels of the board cell (cx, cy)
+// on the board panel starting at x with the given width.
+func hudBoardCell(x, width, cx, cy int) (float64, float64, float64) {
+	size := float64(min(width, HudHeightPixels)-HudImageInnerPaddingPixels*Two) / GridSize
+	return float64(x+HudImageInnerPaddingPixels) + float64(cx)*size,
+		float64(HudTopLeftYPixels+HudImageInnerPaddingPixels) + float64(cy)*size,
+		size
+}
+
+// hudBoardAssist returns the fill of the board cells where the current player would win, or must block
+// the other player, on their next move. It is empty unless the assist is enabled and a round is being played.
+func hudBoardAssist(g *Game) map[BoardCell]color.Color {
+	assist := map[BoardCell]color.Color{}
+	if !g.settings.BoardAssist || g.state != StatePlaying || g.currentPlayer == nil {
+		return assist
+	}
+
+	mode := g.gameMode()
+	symbol := g.currentPlayer.symbol
+	for _, c := range winningCells(mode, &g.board, mode.NextPlayer(symbol)) {
+		assist[c] = ColorHUDBoardBlock
+	}
+	// winning beats blocking
+	for _, c := range winningCells(mode, &g.board, symbol) {
+		assist[c] = ColorHUDBoardWin
+	}
+	return assist
+}
+
+// hudLevel returns the level of the board shown on the HUD, the one the current player stands on.
+func hudLevel(g *Game) int {
+	if g.currentPlayer == nil {
+		return 0
+	}
+	return g.currentPlayer.level
+}
+
+// drawBoardPanel draws the level of the board seen from above: the grid, the marks, the cell of the current player,
+// the assist and the winning line at game over. In the quantum mode the spooky marks are small faded icons
+// in their cells, and the cells a cycle collapses into are numbered like the keys choosing them.
+func drawBoardPanel(screen *ebiten.Image, g *Game, x, width int) {
+	assist := hudBoardAssist(g)
+	level := hudLevel(g)
+
+	for cy, row := range g.board[level] {
+		for cx, symbol := range row {
+			cellX, cellY, size := hudBoardCell(x, width, cx, cy)
+			if col, ok := assist[BoardCell{Col: cx, Row: cy, Level: level}]; ok {
+				vector.FillRect(screen, float32(cellX), float32(cellY), float32(size), float32(size), col, false)
+			}
+			vector.StrokeRect(screen, float32(cellX), float32(cellY), float32(size), float32(size),
+				HudBoardLineWidthPixels, ColorHUDBorder, false)
+
+			if symbol != PlayerSymbolNone {
+				drawTextureIcon(screen, g.assets.Textures[symbol.MarkTextureID()],
+					cellX+HudBoardLineWidthPixels, cellY+HudBoardLineWidthPixels,
+					size-Two*HudBoardLineWidthPixels, ColorHUDText)
+			} else if level == 0 {
+				spooky := g.quantum.MarksIn(BoardCell{Col: cx, Row: cy, Level: level})
+				drawSpookyIcons(screen, g, spooky, cellX, cellY, size)
+			}
+		}
+	}
+
+	// the cell of the current player, found like updatePlaying does to place a mark
+	if g.state == StatePlaying && g.currentPlayer != nil {
+		if cx, cy, ok := g.levelMap(level).BoardCellAt(g.currentPlayer.pos); ok {
+			cellX, cellY, size := hudBoardCell(x, width, cx, cy)
+			vector.StrokeRect(screen, float32(cellX), float32(cellY), float32(size), float32(size),
+				HudBoardCurrentWidth, ColorHUDBoardCurrent, false)
+		}
+	}
+
+	// the cells the cycle collapses into, numbered like the keys choosing them, and the first room of a spooky mark
+	for i, m := range g.collapseChoices() {
+		if m.Cell.Level == level {
+			cellX, cellY, size := hudBoardCell(x, width, m.Cell.Col, m.Cell.Row)
+			vector.StrokeRect(screen, float32(cellX), float32(cellY), float32(size), float32(size),
+				HudBoardCurrentWidth, ColorHUDBoardCollapse, false)
+			g.drawTextWithFace(screen, strconv.Itoa(i+1), cellX+size/Two, cellY+size/Two, Center,
+				ColorHUDBoardCollapse, g.assets.NormalTextFace, TextLineSpacing)
+		}
+	}
+	if c := g.pickedCell; c != nil && c.Level == level {
+		cellX, cellY, size := hudBoardCell(x, width, c.Col, c.Row)
+		vector.StrokeRect(screen, float32(cellX), float32(cellY), float32(size), float32(size),
+			HudBoardCurrentWidth, ColorHUDBoardPicked, false)
+	}
+
+	if len(g.winLine) == 0 {
+		return
+	}
+	// a line through the levels is seen from above, straight up it is a ring around its cell
+	first, last := g.winLine[0], g.winLine[len(g.winLine)-1]
+	x0, y0, size := hudBoardCell(x, width, first.Col, first.Row)
+	x1, y1, _ := hudBoardCell(x, width, last.Col, last.Row)
+	if first.Col == last.Col && first.Row == last.Row {
+		vector.StrokeCircle(screen, float32(x0+size/Two), float32(y0+size/Two), float32(size/Two),
+			HudBoardWinLineWidth, ColorHUDWinLine, true)
+		return
+	}
+	vector.StrokeLine(screen,
+		float32(x0+size/Two), float32(y0+size/Two), float32(x1+size/Two), float32(y1+size/Two),
+		HudBoardWinLineWidth, ColorHUDWinLine, true)
+}
+
+// drawSpookyIcons draws the spooky marks of a cell of the board panel as faded icons, three by row.
+func drawSpookyIcons(screen *ebiten.Image, g *Game, marks []SpookyMark, cellX, cellY, size float64) {
+	icon := size / GridSize
+	for i, s := range marks {
+		x := cellX + float64(i%GridSize)*icon
+		y := cellY + float64(i/GridSize%GridSize)*icon
+		drawFadedTextureIcon(screen, g.assets.Textures[s.Player.MarkTextureID()], x, y, icon, QuantumSpookyFade,
+			ColorHUDText)
+	}
+}
+
+// hudCellName returns the name of the room of the cell as seen on the board panel, "top left" or "center".
+func hudCellName(c BoardCell) string {
+	rows := [GridSize]string{"top", "middle", "bottom"}
+	cols := [GridSize]string{"left", "center", "right"}
+	if c.Row == GridSize/Two && c.Col == GridSize/Two {
+		return cols[c.Col]
+	}
+	return rows[c.Row%GridSize] + " " + cols[c.Col%GridSize]
+}
+
+// hudPlaceLine returns the key line telling how to make a move, following the steps of a quantum move.
+func hudPlaceLine(g *Game) string {
+	if g.currentPlayer == nil {
+		return "E: Place marker"
+	}
+
+	moves := g.gameMode().LegalMoves(&g.board, g.currentPlayer.symbol)
+	switch {
+	case slices.ContainsFunc(moves, func(m Move) bool { return m.Kind == MoveCollapse }):
+		return "1/2: Collapse"
+	case g.pickedCell != nil:
+		return "E: Second room"
+	case slices.ContainsFunc(moves, func(m Move) bool { return m.Kind == MoveSpooky }):
+		return "E: Spooky mark"
+	case len(g.gameMode().Marks(g.currentPlayer.symbol)) > 1:
+		return "E: Place " + g.currentMark().String() + "  Q: Switch"
+	}
+	return "E: Place marker"
+}
+
+// drawCollapsePrompt tells at the top of the screen which spooky mark closed a cycle,
+// and the rooms the current player chooses from to collapse it.
+func drawCollapsePrompt(screen *ebiten.Image, g *Game) {
+	closing, ok := g.quantum.CycleMark()
+	choices := g.collapseChoices()
+	if !ok || len(choices) == 0 || g.state != StatePlaying {
+		return
+	}
+
+	rooms := make([]string, 0, len(choices))
+	for i, m := range choices {
+		rooms = append(rooms, strconv.Itoa(i+1)+": "+hudCellName(m.Cell))
+	}
+	lines := closing.Player.String() + strconv.Itoa(closing.Turn) + " closed a cycle, " +
+		g.currentPlayer.symbol.String() + " chooses where it collapses\n" +
+		strings.Join(rooms, "    ") + "    or E in the room"
+
+	// the prompt stops short of the minimap in the top right corner
+	width := float64(MinimapPosX - MinimapPadding)
+	vector.FillRect(screen, 0, 0, float32(width), HudCollapsePromptHeight, ColorHUDFill, false)
+	g.drawTextWithFace(screen, lines, width/Two, HudCollapsePromptHeight/Two, Center, ColorHUDText,
+		g.assets.NormalTextFace, TextLineSpacing)
+}
+
 // drawTextLines draws multiple lines using a fixed vertical step starting at startY.
 func drawTextLines(g *Game, screen *ebiten.Image, x, startY float64, lines []string) {
 	if g == nil || screen == nil {
@@ -115,7 +309,10 @@ func (h *Hud) Draw(screen *ebiten.Image, g *Game) {
 	namePanelX := playerPanelX + playerPanelWidth
 	namePanelWidth := HudNamePanelWidthPixels
 
-	keysPanelX := namePanelX + namePanelWidth
+	boardPanelX := namePanelX + namePanelWidth
+	boardPanelWidth := HudSquarePanelSizePixels
+
+	keysPanelX := boardPanelX + boardPanelWidth
 	keysPanelWidth := HudKeysPanelWidthPixels
 
 	wasdPanelX := keysPanelX + keysPanelWidth
@@ -123,11 +320,13 @@ func (h *Hud) Draw(screen *ebiten.Image, g *Game) {
 
 	drawPanelFrame(screen, playerPanelX, playerPanelWidth)
 	drawPanelFrame(screen, namePanelX, namePanelWidth)
+	drawPanelFrame(screen, boardPanelX, boardPanelWidth)
 	drawPanelFrame(screen, keysPanelX, keysPanelWidth)
 	drawPanelFrame(screen, wasdPanelX, wasdPanelWidth)
 
 	if g.currentPlayer != nil {
-		playerTexture := g.assets.Textures[g.currentPlayer.symbolTextureID]
+		// the mark drawn next, the player's own symbol unless the mode lets them choose
+		playerTexture := g.assets.Textures[g.currentMark().MarkTextureID()]
 		drawImageContained(
 			screen,
 			playerTexture.Source,
@@ -149,12 +348,15 @@ func (h *Hud) Draw(screen *ebiten.Image, g *Game) {
 
 	currentPlayerScoreLine := "Score: 0"
 	if g.currentPlayer != nil {
-		currentPlayerScoreLine = "Score: " + strconv.Itoa(g.currentPlayer.score)
+		currentPlayerScoreLine = "Score: " + formatScore(g.currentPlayer.score)
+	}
+	if g.levelCount() > 1 {
+		currentPlayerScoreLine += "    Level: " + strconv.Itoa(hudLevel(g)+1) + "/" + strconv.Itoa(g.levelCount())
 	}
 
 	totalScoreLine := "Score  X: 0    O: 0"
 	if g.playerX != nil && g.playerO != nil {
-		totalScoreLine = "Score  X: " + strconv.Itoa(g.playerX.score) + "    O: " + strconv.Itoa(g.playerO.score)
+		totalScoreLine = "Score  X: " + formatScore(g.playerX.score) + "    O: " + formatScore(g.playerO.score)
 	}
 
 	drawTextLines(g, screen, nameTextX, nameTextY, []string{
@@ -163,13 +365,36 @@ func (h *Hud) Draw(screen *ebiten.Image, g *Game) {
 		totalScoreLine,
 	})
 
+	if g.timer.Running {
+		drawTurnCountdown(screen, g, float64(namePanelX+namePanelWidth-HudPanelOuterPaddingXPixels), nameTextY)
+	}
+
+	drawBoardPanel(screen, g, boardPanelX, boardPanelWidth)
+
 	keysTextX := float64(keysPanelX + HudPanelOuterPaddingXPixels)
 	keysTextY := float64(HudTopLeftYPixels + HudPanelOuterPaddingYPixels)
 
+	// on a teleporter pad, how to take it replaces how to quit
+	firstLine := "Esc: Quit"
+	if g.currentPlayer != nil && g.onTeleporter(g.currentPlayer) {
+		firstLine = "F/Shift+F: Level"
+	}
+
 	drawTextLines(g, screen, keysTextX, keysTextY, []string{
-		"Esc: Quit",
+		firstLine,
 		"Ctrl + R: Restart",
-		"E: Place marker",
+		hudPlaceLine(g),
+	})
+
+	muteLine := "M: Mute"
+	if g.settings.Muted {
+		muteLine = "M: Unmute"
+	}
+
+	drawTextLines(g, screen, keysTextX+float64(HudKeysColumnWidthPixels), keysTextY, []string{
+		muteLine,
+		"-/=: Field of view",
+		"F3: Debug  ~: Console",
 	})
 
 	wasdTexture := g.assets.Textures[WasdKeys]
@@ -182,4 +407,6 @@ func (h *Hud) Draw(screen *ebiten.Image, g *Game) {
 		HudHeightPixels,
 		HudImageInnerPaddingPixels,
 	)
+
+	drawCollapsePrompt(screen, g)
 }
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestHudBoardCell_FitsThePanel(t *testing.T) {
+	x := HudSquarePanelSizePixels + HudNamePanelWidthPixels
+	left, top, _ := hudBoardCell(x, HudSquarePanelSizePixels, 0, 0)
+	right, bottom, size := hudBoardCell(x, HudSquarePanelSizePixels, GridSize-1, GridSize-1)
+
+	if left < float64(x) || top < HudTopLeftYPixels {
+		t.Errorf("first cell at (%v,%v) is outside of the panel", left, top)
+	}
+	if right+size > float64(x+HudSquarePanelSizePixels) || bottom+size > WindowSizeY {
+		t.Errorf("last cell ends at (%v,%v), outside of the panel", right+size, bottom+size)
+	}
+	if x+HudSquarePanelSizePixels+HudKeysPanelWidthPixels+HudSquarePanelSizePixels != WindowSizeX {
+		t.Error("the hud panels should cover the window width")
+	}
+}
+
+func TestHudBoardAssist(t *testing.T) {
+	g := &Game{state: StatePlaying, currentPlayer: NewPlayer(0, 0, PlayerSymbolO, "O")}
+	g.board[0] = Board{
+		{PlayerSymbolX, PlayerSymbolX, PlayerSymbolNone},
+		{PlayerSymbolO, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolX, PlayerSymbolNone, PlayerSymbolNone},
+	}
+
+	if got := hudBoardAssist(g); len(got) != 0 {
+		t.Errorf("the assist is off by default, got %v", got)
+	}
+
+	g.settings.BoardAssist = true
+	got := hudBoardAssist(g)
+	if len(got) != 2 || got[BoardCell{Col: 2, Row: 1}] != ColorHUDBoardWin ||
+		got[BoardCell{Col: 2, Row: 0}] != ColorHUDBoardBlock {
+		t.Errorf("hudBoardAssist() = %v, want a win at (2,1) and a block at (2,0)", got)
+	}
+
+	g.state = StateGameOver
+	if got = hudBoardAssist(g); len(got) != 0 {
+		t.Errorf("no assist after the round, got %v", got)
+	}
+}
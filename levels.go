@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+// newLevelMaps returns the maps of the levels of the dungeon, bottom first: the ground map, and above it copies
+// of its layout without the decorations, where the plain brick walls change with the level so each one is told apart.
+// A single level returns nil, the ground map is used on its own.
+func newLevelMaps(ground Map, count int) []Map {
+	if count <= 1 {
+		return nil
+	}
+
+	levels := make([]Map, 0, count)
+	levels = append(levels, ground)
+	for level := 1; level < count; level++ {
+		m := ground.Clone()
+		m.Sprites = nil
+
+		wall := levelWall(level)
+		for _, row := range m.Tiles {
+			for x, tile := range row {
+				if tile == TileID(WallBrick) {
+					row[x] = wall
+				}
+			}
+		}
+		levels = append(levels, m)
+	}
+	return levels
+}
+
+// levelWall returns the tile replacing the plain brick walls on the level.
+func levelWall(level int) TileID {
+	walls := [...]TextureID{WallBrick, WallBrickHole, WallBrickGopher}
+	return TileID(walls[level%len(walls)])
+}
+
+// teleporterPads returns the positions of the teleporter pads of a level, on the spawns of the players
+// so both start next to one.
+func teleporterPads(m Map) []Vec2 {
+	return []Vec2{m.Spawn(PlayerSymbolX), m.Spawn(PlayerSymbolO)}
+}
+
+// levelMap returns the map of the level of the dungeon, the ground map outside of the cube mode.
+func (g *Game) levelMap(level int) Map {
+	if level > 0 && level < len(g.levels) {
+		return g.levels[level]
+	}
+	return g.worldMap
+}
+
+// levelCount returns the number of levels of the dungeon, 1 outside of the cube mode.
+func (g *Game) levelCount() int {
+	return max(len(g.levels), 1)
+}
+
+// setLevels builds the levels the game mode plays on from the ground map and spawns their decorations.
+// Players standing on a level that no longer exists go back to the ground.
+func (g *Game) setLevels() error {
+	g.levels = newLevelMaps(g.worldMap, g.gameMode().Levels())
+	for _, p := range []*Player{g.playerX, g.playerO} {
+		if p.level >= g.levelCount() {
+			p.level = 0
+		}
+	}
+	return g.spawnDecorations(g.worldMap)
+}
+
+// teleporterSprites returns the pads of every level, none outside of the cube mode.
+func (g *Game) teleporterSprites() []*Sprite {
+	var sprites []*Sprite
+	for level, m := range g.levels {
+		for _, pad := range teleporterPads(m) {
+			sprites = append(sprites, &Sprite{
+				Position:  pad,
+				TextureID: Teleporter,
+				Scale:     TeleporterScale,
+				Z:         TeleporterZ,
+				Level:     level,
+			})
+		}
+	}
+	return sprites
+}
+
+// onTeleporter returns true if the player stands close enough to a teleporter pad to take it.
+func (g *Game) onTeleporter(p *Player) bool {
+	if g.levelCount() <= 1 {
+		return false
+	}
+	for _, pad := range teleporterPads(g.levelMap(p.level)) {
+		if pad.Sub(p.pos).Len2() <= TeleporterRadius*TeleporterRadius {
+			return true
+		}
+	}
+	return false
+}
+
+// takeTeleporter moves the player standing on a pad step levels up, wrapping around, and returns true.
+// The levels share their layout, so the player keeps their position and arrives on the pad of the other level.
+func (g *Game) takeTeleporter(p *Player, step int) bool {
+	if !g.onTeleporter(p) {
+		return false
+	}
+
+	p.level = cycleIndex(p.level, g.levelCount(), step)
+
+	// a puff of smoke shows the arrival
+	g.spawnPoof(p.pos, p.level)
+	return true
+}
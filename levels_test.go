@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestNewLevelMaps(t *testing.T) {
+	ground := NewMap()
+	if got := newLevelMaps(ground, 1); got != nil {
+		t.Fatalf("a single level should use the ground map alone, got %d maps", len(got))
+	}
+
+	levels := newLevelMaps(ground, GridSize)
+	if len(levels) != GridSize {
+		t.Fatalf("newLevelMaps() = %d maps, want %d", len(levels), GridSize)
+	}
+
+	for level, m := range levels[1:] {
+		if err := m.Validate(); err != nil {
+			t.Errorf("level %d: %v", level+1, err)
+		}
+		if len(m.Sprites) != 0 {
+			t.Errorf("level %d has %d decorations, want none", level+1, len(m.Sprites))
+		}
+		for y, row := range m.Tiles {
+			for x, tile := range row {
+				if (tile == TileEmpty) != (ground.Tiles[y][x] == TileEmpty) {
+					t.Fatalf("level %d: tile (%d,%d) is not walkable like the ground", level+1, x, y)
+				}
+			}
+		}
+	}
+
+	if levels[1].Tiles[0][0] == ground.Tiles[0][0] || levels[1].Tiles[0][0] == levels[2].Tiles[0][0] {
+		t.Error("the brick walls of each level should look different")
+	}
+	if ground.Tiles[0][0] != TileID(WallBrick) {
+		t.Error("building the levels should not change the ground map")
+	}
+}
+
+func TestGame_TakeTeleporter(t *testing.T) {
+	m := NewMap()
+	pX := NewPlayer(m.Spawn(PlayerSymbolX).X, m.Spawn(PlayerSymbolX).Y, PlayerSymbolX, "X")
+	pO := NewPlayer(1.5, 1.5, PlayerSymbolO, "O")
+	g := &Game{worldMap: m, playerX: pX, playerO: pO, currentPlayer: pX}
+
+	if err := g.setLevels(); err != nil {
+		t.Fatal(err)
+	}
+	if g.levelCount() != 1 || g.takeTeleporter(pX, 1) {
+		t.Fatal("the classic mode has no teleporter")
+	}
+
+	g.mode = CubeMode{}
+	if err := g.setLevels(); err != nil {
+		t.Fatal(err)
+	}
+	if pads := len(g.teleporterSprites()); pads != 2*GridSize {
+		t.Errorf("%d teleporter pads, want one per player spawn on each level", pads)
+	}
+
+	if g.takeTeleporter(pO, 1) || pO.level != 0 {
+		t.Error("a player away from the pads cannot change level")
+	}
+
+	for _, want := range []int{1, 2, 0} {
+		if !g.takeTeleporter(pX, 1) || pX.level != want {
+			t.Errorf("teleporter up: level %d, want %d", pX.level, want)
+		}
+	}
+	if !g.takeTeleporter(pX, -1) || pX.level != GridSize-1 {
+		t.Errorf("teleporter down from the ground: level %d, want the top one", pX.level)
+	}
+
+	// the arrival puff is the one of the asset pack
+	types := maps.Clone(spriteTypes)
+	packPoof := types["poof"]
+	packPoof.TextureID = SkeletonSkull
+	types["poof"] = packPoof
+	g.assets = &Assets{SpriteTypes: types}
+	g.takeTeleporter(pX, 1)
+	if poof := g.sprites[len(g.sprites)-1]; poof.TextureID != SkeletonSkull || poof.Level != pX.level {
+		t.Errorf("arrival puff %+v, want the pack one on level %d", poof, pX.level)
+	}
+
+	// back to a flat mode, the player returns to the ground
+	g.mode = ClassicMode{}
+	if err := g.setLevels(); err != nil {
+		t.Fatal(err)
+	}
+	if pX.level != 0 || len(g.teleporterSprites()) != 0 {
+		t.Errorf("level %d after leaving the cube mode, want the ground without pads", pX.level)
+	}
+}
+
+func TestGame_CubeMarkOnItsLevel(t *testing.T) {
+	m := NewMap()
+	pX := NewPlayer(m.Spawn(PlayerSymbolX).X, m.Spawn(PlayerSymbolX).Y, PlayerSymbolX, "X")
+	pO := NewPlayer(m.Spawn(PlayerSymbolO).X, m.Spawn(PlayerSymbolO).Y, PlayerSymbolO, "O")
+	g := &Game{worldMap: m, playerX: pX, playerO: pO, currentPlayer: pX, mode: CubeMode{}}
+	if err := g.setLevels(); err != nil {
+		t.Fatal(err)
+	}
+	g.takeTeleporter(pX, 1)
+
+	cell := BoardCell{Col: 1, Row: 1, Level: 1}
+	if err := g.playMove(Move{Cell: cell, Player: PlayerSymbolX, Mark: PlayerSymbolX}); err != nil {
+		t.Fatal(err)
+	}
+	if g.board.At(cell) != PlayerSymbolX || g.board[0][1][1] != PlayerSymbolNone {
+		t.Fatalf("board = %v, want the mark on the middle level only", g.board)
+	}
+
+	// the mark is seen from its level only
+	seen := func(p *Player) bool {
+		for _, s := range worldSprites(g, p) {
+			if s.TextureID == PlayerXSymbol {
+				return true
+			}
+		}
+		return false
+	}
+	if !seen(pX) || seen(pO) {
+		t.Error("the mark should be seen from the middle level and not from the ground")
+	}
+}
+
+func TestMinimap_CycleLevel(t *testing.T) {
+	p := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	g := &Game{currentPlayer: p, levels: newLevelMaps(NewMap(), GridSize)}
+	var m Minimap
+
+	m.CycleLevel(g, 1)
+	if m.shownLevel(g) != 1 {
+		t.Fatalf("shown level %d, want the level above the player", m.shownLevel(g))
+	}
+
+	// the pinned level stays when the player moves
+	p.level = 2
+	if m.shownLevel(g) != 1 {
+		t.Errorf("shown level %d, want the selected level", m.shownLevel(g))
+	}
+
+	// selecting the level of the player follows them again
+	m.CycleLevel(g, 1)
+	p.level = 0
+	if m.shownLevel(g) != 0 {
+		t.Errorf("shown level %d, want the level of the player", m.shownLevel(g))
+	}
+}
+
+func TestCamerasChangeLevel(t *testing.T) {
+	from := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	to := NewPlayer(9.5, 1.5, PlayerSymbolO, "O")
+	to.level = 2
+
+	var tr TurnTransition
+	tr.Start(from, to)
+	if tr.Camera().level != 0 {
+		t.Errorf("transition starts on level %d, want the level of the previous player", tr.Camera().level)
+	}
+	tr.Update(1)
+	if tr.Camera().level != 2 {
+		t.Errorf("transition ends on level %d, want the level of the next player", tr.Camera().level)
+	}
+
+	var f Flyover
+	f.Start(NewMap(), []BoardCell{{Col: 1, Row: 1, Level: 0}, {Col: 1, Row: 1, Level: 1}, {Col: 1, Row: 1, Level: 2}})
+	f.Update(0.9)
+	if f.Camera().level != 2 {
+		t.Errorf("flyover ends on level %d, want the top of the column", f.Camera().level)
+	}
+}
@@ -5,18 +5,34 @@ package main
 
 import (
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
-	game, err := NewGame()
+	// gopher-dungeon render -out shot.png renders a frame without opening a window
+	if len(os.Args) > 1 && os.Args[1] == RenderCommandName {
+		if err := runRenderCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	settings, errS := loadSettings(os.Args[1:])
+	if errS != nil {
+		slog.Warn("settings", "error", errS)
+	}
+
+	game, err := NewGame(settings)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	ebiten.SetWindowSize(WindowSizeX, WindowSizeY)
 	ebiten.SetWindowTitle(WindowTitle)
+	ebiten.SetFullscreen(settings.Fullscreen)
 
 	if errG := ebiten.RunGame(game); errG != nil {
 		log.Fatal(errG)
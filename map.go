@@ -3,6 +3,18 @@
 
 package main
 
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
 // TileID represents the type of a tile in the world map.
 type TileID uint8
 
@@ -11,13 +23,116 @@ const (
 )
 
 // Map represents the game world as a grid of tiles.
+// Faces optionally overrides the texture of single faces of wall tiles.
+// Rooms are the board cells, a map without rooms uses the legacy grid of MapRoomStride tiles.
+// Spawns are the start positions by player, the default ones are used for missing players.
+// Sprites are the decorations placed in the map.
 type Map struct {
-	Tiles [][]TileID
+	Tiles   [][]TileID
+	Faces   map[TileCoord]FaceTextures
+	Rooms   []Room
+	Spawns  map[PlayerSymbol]Vec2
+	Sprites []MapSprite
+}
+
+// Room is a rectangle of tiles holding the board cell (Col, Row).
+// Standing on any of its tiles selects the cell, and marks appear at its center.
+type Room struct {
+	X, Y     int
+	W, H     int
+	Col, Row int
+}
+
+// MapSprite is a decoration of the map, Type is a sprite type name like "light".
+type MapSprite struct {
+	Type     string
+	Position Vec2
+}
+
+// TileCoord is the position of a tile in the map grid.
+type TileCoord struct {
+	X, Y int
+}
+
+// FaceTextures holds a texture per wall face, indexed by WallFace. 0 keeps the tile texture.
+type FaceTextures [FaceCount]TextureID
+
+// mapFile is the json representation of a map stored on disk.
+// tiles is a list of rows, 0 is an empty tile and any other value a wall texture.
+// faces lists the wall tiles having a different texture on some of their faces.
+// rooms, spawns (by player "X" or "O") and sprites are optional, see Map.
+type mapFile struct {
+	Tiles   [][]TileID          `json:"tiles"`
+	Faces   []mapFace           `json:"faces,omitempty"`
+	Rooms   []mapRoom           `json:"rooms,omitempty"`
+	Spawns  map[string]mapPoint `json:"spawns,omitempty"`
+	Sprites []mapSprite         `json:"sprites,omitempty"`
+}
+
+// mapFace is the json representation of the face textures of one wall tile.
+type mapFace struct {
+	X     int       `json:"x"`
+	Y     int       `json:"y"`
+	North TextureID `json:"north,omitempty"`
+	East  TextureID `json:"east,omitempty"`
+	South TextureID `json:"south,omitempty"`
+	West  TextureID `json:"west,omitempty"`
+}
+
+// mapRoom is the json representation of a room.
+type mapRoom struct {
+	X   int `json:"x"`
+	Y   int `json:"y"`
+	W   int `json:"w"`
+	H   int `json:"h"`
+	Col int `json:"col"`
+	Row int `json:"row"`
+}
+
+type mapPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type mapSprite struct {
+	Type string  `json:"type"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
 }
 
 // NewMap returns the default world map.
+//
+//nolint:mnd // positions on the map
 func NewMap() Map {
+	// the legacy grid of rooms
+	rooms := make([]Room, 0, GridSize*GridSize)
+	for row := range GridSize {
+		for col := range GridSize {
+			rooms = append(rooms, Map{}.BoardCellRoom(col, row))
+		}
+	}
+
 	return Map{
+		Faces: nil,
+		Rooms: rooms,
+		Spawns: map[PlayerSymbol]Vec2{
+			PlayerSymbolX: defaultPlayerXSpawn(),
+			PlayerSymbolO: defaultPlayerOSpawn(),
+		},
+		Sprites: []MapSprite{
+			{Type: "light", Position: Vec2{X: 2.2, Y: 4.7}},
+			{Type: "light", Position: Vec2{X: 11.5, Y: 1.4}},
+			{Type: "light", Position: Vec2{X: 8.4, Y: 14.6}},
+			{Type: "light", Position: Vec2{X: 18.2, Y: 18.6}},
+			{Type: "skull", Position: Vec2{X: 2.9, Y: 5.3}},
+			{Type: "skull", Position: Vec2{X: 10.6, Y: 5.1}},
+			{Type: "skull", Position: Vec2{X: 15.9, Y: 4.4}},
+			{Type: "skull", Position: Vec2{X: 5.2, Y: 11.7}},
+			{Type: "skull", Position: Vec2{X: 12.7, Y: 12.4}},
+			{Type: "skull", Position: Vec2{X: 17.1, Y: 17.3}},
+			{Type: "chains", Position: Vec2{X: 6.4, Y: 3.2}},
+			{Type: "chains", Position: Vec2{X: 14.8, Y: 10.6}},
+		},
 		Tiles: [][]TileID{
 			{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1},
 			{1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1},
@@ -45,6 +160,382 @@ func NewMap() Map {
 	}
 }
 
+// LoadMapFile reads and validates a json map file.
+func LoadMapFile(path string) (Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Map{}, fmt.Errorf("read map %q: %w", path, err)
+	}
+
+	m, err := ParseMap(data)
+	if err != nil {
+		return Map{}, fmt.Errorf("parse map %q: %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseMap decodes a json map and checks that it is a non empty rectangle closed by walls.
+func ParseMap(data []byte) (Map, error) {
+	var f mapFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Map{}, fmt.Errorf("decode map: %w", err)
+	}
+
+	m := Map{Tiles: f.Tiles, Faces: nil, Rooms: nil, Spawns: nil, Sprites: nil}
+	for _, face := range f.Faces {
+		if m.Faces == nil {
+			m.Faces = make(map[TileCoord]FaceTextures, len(f.Faces))
+		}
+		m.Faces[TileCoord{X: face.X, Y: face.Y}] = FaceTextures{
+			FaceNorth: face.North,
+			FaceEast:  face.East,
+			FaceSouth: face.South,
+			FaceWest:  face.West,
+		}
+	}
+
+	for _, r := range f.Rooms {
+		m.Rooms = append(m.Rooms, Room{X: r.X, Y: r.Y, W: r.W, H: r.H, Col: r.Col, Row: r.Row})
+	}
+
+	for name, p := range f.Spawns {
+		symbol, err := parseSymbol(name)
+		if err != nil || symbol == PlayerSymbolNone {
+			return Map{}, fmt.Errorf("spawn of unknown player %q", name)
+		}
+		if m.Spawns == nil {
+			m.Spawns = make(map[PlayerSymbol]Vec2, len(f.Spawns))
+		}
+		m.Spawns[symbol] = Vec2{X: p.X, Y: p.Y}
+	}
+
+	for _, s := range f.Sprites {
+		m.Sprites = append(m.Sprites, MapSprite{Type: s.Type, Position: Vec2{X: s.X, Y: s.Y}})
+	}
+
+	if err := m.Validate(); err != nil {
+		return Map{}, err
+	}
+	return m, nil
+}
+
+// SaveMapFile writes the map to a json file readable by LoadMapFile.
+func SaveMapFile(path string, m Map) error {
+	data, err := EncodeMap(m)
+	if err != nil {
+		return err
+	}
+	if errW := os.WriteFile(path, data, 0o600); errW != nil {
+		return fmt.Errorf("write map %q: %w", path, errW)
+	}
+	return nil
+}
+
+// EncodeMap returns the json representation of the map, the inverse of ParseMap.
+// Every row of tiles and every list entry is written on its own line so map files stay readable and diffable,
+// and entries are sorted so saving the same map twice gives the same file.
+func EncodeMap(m Map) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	// []TileID would be encoded as base64 like any byte slice
+	rows := make([]any, 0, len(m.Tiles))
+	for _, row := range m.Tiles {
+		ints := make([]int, len(row))
+		for x, tile := range row {
+			ints[x] = int(tile)
+		}
+		rows = append(rows, ints)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	writeMapList(&buf, "tiles", rows)
+
+	for _, section := range []struct {
+		key   string
+		items []any
+	}{
+		{key: "faces", items: m.faceEntries()},
+		{key: "rooms", items: m.roomEntries()},
+		{key: "sprites", items: m.spriteEntries()},
+	} {
+		if len(section.items) > 0 {
+			buf.WriteString(",\n")
+			writeMapList(&buf, section.key, section.items)
+		}
+	}
+
+	if len(m.Spawns) > 0 {
+		spawns := make(map[string]mapPoint, len(m.Spawns))
+		for symbol, p := range m.Spawns {
+			spawns[symbol.String()] = mapPoint{X: p.X, Y: p.Y}
+		}
+		// map keys are sorted by encoding/json
+		data, err := json.Marshal(spawns)
+		if err != nil {
+			return nil, fmt.Errorf("encode spawns: %w", err)
+		}
+		fmt.Fprintf(&buf, ",\n  \"spawns\": %s", data)
+	}
+
+	buf.WriteString("\n}\n")
+	return buf.Bytes(), nil
+}
+
+// writeMapList writes a top level list of an encoded map, one entry per line.
+// entries are plain values of the map file types, they cannot fail to encode.
+func writeMapList(buf *bytes.Buffer, key string, items []any) {
+	fmt.Fprintf(buf, "  %q: [\n", key)
+	for i, item := range items {
+		data, _ := json.Marshal(item)
+		buf.WriteString("    ")
+		buf.Write(data)
+		if i < len(items)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("  ]")
+}
+
+// faceEntries returns the face overrides as json entries, sorted by row then column.
+func (m Map) faceEntries() []any {
+	coords := slices.SortedFunc(maps.Keys(m.Faces), func(a, b TileCoord) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+
+	entries := make([]any, 0, len(coords))
+	for _, c := range coords {
+		f := m.Faces[c]
+		entries = append(entries, mapFace{
+			X: c.X, Y: c.Y,
+			North: f[FaceNorth], East: f[FaceEast], South: f[FaceSouth], West: f[FaceWest],
+		})
+	}
+	return entries
+}
+
+// roomEntries returns the rooms as json entries, sorted by board cell.
+func (m Map) roomEntries() []any {
+	rooms := slices.Clone(m.Rooms)
+	slices.SortFunc(rooms, func(a, b Room) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+	})
+
+	entries := make([]any, 0, len(rooms))
+	for _, r := range rooms {
+		entries = append(entries, mapRoom{X: r.X, Y: r.Y, W: r.W, H: r.H, Col: r.Col, Row: r.Row})
+	}
+	return entries
+}
+
+// spriteEntries returns the sprites as json entries, in map order.
+func (m Map) spriteEntries() []any {
+	entries := make([]any, 0, len(m.Sprites))
+	for _, s := range m.Sprites {
+		entries = append(entries, mapSprite{Type: s.Type, X: s.Position.X, Y: s.Position.Y})
+	}
+	return entries
+}
+
+// Validate checks that the map is a non empty rectangle and that its border is only made of walls,
+// so rays and players can never leave the map.
+func (m Map) Validate() error {
+	width, height := m.Width(), m.Height()
+	if width == 0 || height == 0 {
+		return errors.New("map is empty")
+	}
+
+	for y, row := range m.Tiles {
+		if len(row) != width {
+			return fmt.Errorf("row %d has %d tiles, expected %d", y, len(row), width)
+		}
+		for x, tile := range row {
+			onBorder := x == 0 || y == 0 || x == width-1 || y == height-1
+			if onBorder && tile == TileEmpty {
+				return fmt.Errorf("border tile (%d,%d) must be a wall", x, y)
+			}
+		}
+	}
+
+	for coord := range m.Faces {
+		tile, outOfBounds := m.GetTileID(coord.X, coord.Y)
+		if outOfBounds || tile == TileEmpty {
+			return fmt.Errorf("face textures at (%d,%d) must be on a wall tile", coord.X, coord.Y)
+		}
+	}
+
+	if err := m.validateRooms(); err != nil {
+		return err
+	}
+
+	for symbol, pos := range m.Spawns {
+		if !m.IsWalkable(pos) {
+			return fmt.Errorf("spawn of %s at (%.2f,%.2f) must be on an empty tile", symbol, pos.X, pos.Y)
+		}
+	}
+
+	for _, s := range m.Sprites {
+		if !m.Contains(s.Position) {
+			return fmt.Errorf("sprite %q at (%.2f,%.2f) is outside of the map", s.Type, s.Position.X, s.Position.Y)
+		}
+	}
+	return nil
+}
+
+// validateRooms checks that the rooms are inside the map, do not overlap and hold distinct board cells.
+// A map has either no rooms, the legacy grid is used then and must fit in the map, or one room per board cell:
+// a cell without a room could never be claimed and the board never filled.
+func (m Map) validateRooms() error {
+	cells := make(map[[2]int]bool, len(m.Rooms))
+	for i, r := range m.Rooms {
+		if r.W <= 0 || r.H <= 0 || r.X < 0 || r.Y < 0 || r.X+r.W > m.Width() || r.Y+r.H > m.Height() {
+			return fmt.Errorf("room %d (%d,%d %dx%d) is outside of the map", i, r.X, r.Y, r.W, r.H)
+		}
+		if r.Col < 0 || r.Col >= GridSize || r.Row < 0 || r.Row >= GridSize {
+			return fmt.Errorf("room %d holds cell (%d,%d) outside of the board", i, r.Row, r.Col)
+		}
+
+		cell := [2]int{r.Col, r.Row}
+		if cells[cell] {
+			return fmt.Errorf("cell (%d,%d) has more than one room", r.Row, r.Col)
+		}
+		cells[cell] = true
+
+		for j, other := range m.Rooms[:i] {
+			if r.Overlaps(other) {
+				return fmt.Errorf("room %d overlaps room %d", i, j)
+			}
+		}
+	}
+
+	if len(m.Rooms) == 0 {
+		// every cell of the legacy grid must be inside the map
+		if size := GridSize * MapRoomStride; m.Width() < size || m.Height() < size {
+			return fmt.Errorf("a map without rooms needs %dx%d tiles for the board, it has %dx%d",
+				size, size, m.Width(), m.Height())
+		}
+		return nil
+	}
+	if len(m.Rooms) == GridSize*GridSize {
+		return nil
+	}
+	var missing []string
+	for row := range GridSize {
+		for col := range GridSize {
+			if !cells[[2]int{col, row}] {
+				missing = append(missing, fmt.Sprintf("(%d,%d)", row, col))
+			}
+		}
+	}
+	return fmt.Errorf("no room for the board cells %s", strings.Join(missing, ", "))
+}
+
+// Contains returns true if the tile (x, y) is part of the room.
+func (r Room) Contains(x, y int) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
+}
+
+// Overlaps returns true if the rooms share at least one tile.
+func (r Room) Overlaps(o Room) bool {
+	return r.X < o.X+o.W && o.X < r.X+r.W && r.Y < o.Y+o.H && o.Y < r.Y+r.H
+}
+
+// Center returns the world position of the center of the room.
+func (r Room) Center() Vec2 {
+	return Vec2{X: float64(r.X) + float64(r.W)/Two, Y: float64(r.Y) + float64(r.H)/Two}
+}
+
+// RoomAt returns the index of the room containing the tile (x, y), ok is false outside of every room.
+func (m Map) RoomAt(x, y int) (int, bool) {
+	for i, r := range m.Rooms {
+		if r.Contains(x, y) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// BoardCellAt returns the board cell (column, row) of the room containing the world position.
+// ok is false when the position is in no room.
+func (m Map) BoardCellAt(pos Vec2) (int, int, bool) {
+	if len(m.Rooms) == 0 {
+		return boardCellAt(pos)
+	}
+	if pos.X < 0 || pos.Y < 0 {
+		return 0, 0, false
+	}
+
+	i, ok := m.RoomAt(int(pos.X), int(pos.Y))
+	if !ok {
+		return 0, 0, false
+	}
+	return m.Rooms[i].Col, m.Rooms[i].Row, true
+}
+
+// BoardCellCenter returns the world position where the mark of the board cell is shown.
+func (m Map) BoardCellCenter(cx, cy int) Vec2 {
+	return m.BoardCellRoom(cx, cy).Center()
+}
+
+// BoardCellRoom returns the room of the board cell, a cell without a room falls back to the legacy grid.
+func (m Map) BoardCellRoom(cx, cy int) Room {
+	for _, r := range m.Rooms {
+		if r.Col == cx && r.Row == cy {
+			return r
+		}
+	}
+	return Room{
+		X: cx * MapRoomStride, Y: cy * MapRoomStride,
+		W: MapRoomStride, H: MapRoomStride,
+		Col: cx, Row: cy,
+	}
+}
+
+// Spawn returns the start position of the player, the default one when the map has none.
+func (m Map) Spawn(symbol PlayerSymbol) Vec2 {
+	if pos, ok := m.Spawns[symbol]; ok {
+		return pos
+	}
+	if symbol == PlayerSymbolO {
+		return defaultPlayerOSpawn()
+	}
+	return defaultPlayerXSpawn()
+}
+
+// NewSprites creates the decoration sprites of the map from the given types.
+// Sprites of unknown types are skipped, animations are staggered so neighbours do not play in sync.
+func (m Map) NewSprites(types map[string]SpriteType) []*Sprite {
+	sprites := make([]*Sprite, 0, len(m.Sprites))
+	for i, ms := range m.Sprites {
+		t, ok := types[ms.Type]
+		if !ok {
+			continue
+		}
+		s := t.NewSprite(ms.Position)
+		s.Anim = NewAnimationState(t.Animation, float64(i)*MapSpriteAnimationStagger)
+		sprites = append(sprites, s)
+	}
+	return sprites
+}
+
+// Clone returns a deep copy of the map, edits of the copy do not change the original.
+func (m Map) Clone() Map {
+	tiles := make([][]TileID, len(m.Tiles))
+	for y, row := range m.Tiles {
+		tiles[y] = slices.Clone(row)
+	}
+	return Map{
+		Tiles:   tiles,
+		Faces:   maps.Clone(m.Faces),
+		Rooms:   slices.Clone(m.Rooms),
+		Spawns:  maps.Clone(m.Spawns),
+		Sprites: slices.Clone(m.Sprites),
+	}
+}
+
 // TextureID returns the texture ID for the tile type.
 // Returns ok=false for empty tiles.
 func (t TileID) TextureID() (TextureID, bool) {
@@ -54,6 +545,20 @@ func (t TileID) TextureID() (TextureID, bool) {
 	return TextureID(t), true
 }
 
+// WallTexture returns the texture of the given face of the wall tile at (x, y).
+// Face overrides win over the tile texture, ok is false for empty or out of bounds tiles.
+func (m Map) WallTexture(x, y int, face WallFace) (TextureID, bool) {
+	tileID, outOfBounds := m.GetTileID(x, y)
+	if outOfBounds {
+		return 0, false
+	}
+
+	if faces, ok := m.Faces[TileCoord{X: x, Y: y}]; ok && face < FaceCount && faces[face] != 0 {
+		return faces[face], true
+	}
+	return tileID.TextureID()
+}
+
 // Width returns the width of the map in tiles.
 func (m Map) Width() int {
 	if len(m.Tiles) == 0 {
@@ -67,6 +572,11 @@ func (m Map) Height() int {
 	return len(m.Tiles)
 }
 
+// Contains returns true if the given position is inside the map bounds.
+func (m Map) Contains(pos Vec2) bool {
+	return pos.X >= 0 && pos.Y >= 0 && pos.X < float64(m.Width()) && pos.Y < float64(m.Height())
+}
+
 // IsWalkable returns true if the given position is walkable (not a wall).
 func (m Map) IsWalkable(pos Vec2) bool {
 	x, y := int(pos.X), int(pos.Y)
@@ -83,3 +593,31 @@ func (m Map) GetTileID(x, y int) (TileID, bool) {
 	}
 	return m.Tiles[y][x], false
 }
+
+// MaxRayIterations returns the number of dda steps needed for a ray to cross the whole map.
+func (m Map) MaxRayIterations() int {
+	return m.Width() + m.Height()
+}
+
+// NearestWalkable returns the center of the walkable tile closest to pos.
+// pos itself is returned if it is already walkable, ok is false if the map has no walkable tile.
+func (m Map) NearestWalkable(pos Vec2) (Vec2, bool) {
+	if m.IsWalkable(pos) {
+		return pos, true
+	}
+
+	best := Vec2{}
+	bestDist := -1.0
+	for y := range m.Height() {
+		for x := range m.Width() {
+			if m.Tiles[y][x] != TileEmpty {
+				continue
+			}
+			center := Vec2{X: float64(x) + HalfTile, Y: float64(y) + HalfTile}
+			if d := center.Sub(pos).Len2(); bestDist < 0 || d < bestDist {
+				best, bestDist = center, d
+			}
+		}
+	}
+	return best, bestDist >= 0
+}
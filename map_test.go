@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testBoardRooms returns the json rooms of a board of one tile rooms in the top left corner of a map,
+// so that small test maps hold every board cell.
+func testBoardRooms() string {
+	rooms := make([]string, 0, GridSize*GridSize)
+	for row := range GridSize {
+		for col := range GridSize {
+			rooms = append(rooms, fmt.Sprintf(`{"x": %d, "y": %d, "w": 1, "h": 1, "col": %d, "row": %d}`,
+				col, row, col, row))
+		}
+	}
+	return `"rooms": [` + strings.Join(rooms, ", ") + `]`
+}
+
+func TestParseMap(t *testing.T) {
+	m, err := ParseMap([]byte(`{"tiles": [[1,1,1],[1,0,2],[1,1,1]], ` + testBoardRooms() + `}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Width() != 3 || m.Height() != 3 {
+		t.Errorf("size = %dx%d, want 3x3", m.Width(), m.Height())
+	}
+	if tile, _ := m.GetTileID(2, 1); tile != 2 {
+		t.Errorf("tile (2,1) = %d, want 2", tile)
+	}
+}
+
+func TestParseMap_Faces(t *testing.T) {
+	m, err := ParseMap([]byte(`{"tiles": [[1,1,1],[1,0,1],[1,1,1]], "faces": [{"x": 2, "y": 1, "west": 3}], ` +
+		testBoardRooms() + `}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id, ok := m.WallTexture(2, 1, FaceWest); !ok || id != 3 {
+		t.Errorf("west face = %d, %v, want 3", id, ok)
+	}
+	if id, ok := m.WallTexture(2, 1, FaceNorth); !ok || id != 1 {
+		t.Errorf("north face = %d, %v, want the tile texture 1", id, ok)
+	}
+	if _, ok := m.WallTexture(1, 1, FaceWest); ok {
+		t.Error("empty tile should have no texture")
+	}
+}
+
+func TestParseMap_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Not json", data: `tiles`},
+		{name: "Empty", data: `{"tiles": []}`},
+		{name: "Ragged rows", data: `{"tiles": [[1,1,1],[1,0],[1,1,1]]}`},
+		{name: "Open border", data: `{"tiles": [[1,1,1],[0,0,1],[1,1,1]]}`},
+		{name: "Face on empty tile", data: `{"tiles": [[1,1,1],[1,0,1],[1,1,1]], "faces": [{"x": 1, "y": 1, "north": 2}]}`},
+		{name: "Board outside of the map", data: `{"tiles": [[1,1,1],[1,0,1],[1,1,1]]}`},
+		{name: "Face out of map", data: `{"tiles": [[1,1,1],[1,0,1],[1,1,1]], "faces": [{"x": 5, "y": 1, "north": 2}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMap([]byte(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNewMap_IsValid(t *testing.T) {
+	if err := NewMap().Validate(); err != nil {
+		t.Errorf("default map is invalid: %v", err)
+	}
+}
+
+func TestMap_NearestWalkable(t *testing.T) {
+	m := NewMap()
+
+	// already walkable
+	pos := Vec2{1.5, 1.5}
+	if got, ok := m.NearestWalkable(pos); !ok || got != pos {
+		t.Errorf("NearestWalkable(%v) = %v, %v, want the same position", pos, got, ok)
+	}
+
+	// inside the wall at (7,1), the closest free tile is (6,1) or (8,1)
+	got, ok := m.NearestWalkable(Vec2{7.2, 1.5})
+	if !ok || !m.IsWalkable(got) {
+		t.Fatalf("NearestWalkable returned %v, %v", got, ok)
+	}
+	if got != (Vec2{6.5, 1.5}) && got != (Vec2{8.5, 1.5}) {
+		t.Errorf("NearestWalkable = %v, want a tile next to the wall", got)
+	}
+}
+
+func TestParseMap_RoomsSpawnsSprites(t *testing.T) {
+	// one tile rooms, the last column of the map is in no room
+	m, err := ParseMap([]byte(`{
+		"tiles": [[1,1,1,1,1,1],[1,0,0,0,0,1],[1,0,0,0,0,1],[1,0,0,0,0,1],[1,1,1,1,1,1]],
+		"rooms": [
+			{"x": 1, "y": 1, "w": 1, "h": 1, "col": 0, "row": 0},
+			{"x": 2, "y": 1, "w": 1, "h": 1, "col": 1, "row": 0},
+			{"x": 3, "y": 1, "w": 1, "h": 1, "col": 2, "row": 0},
+			{"x": 1, "y": 2, "w": 1, "h": 1, "col": 0, "row": 1},
+			{"x": 2, "y": 2, "w": 1, "h": 1, "col": 1, "row": 1},
+			{"x": 3, "y": 2, "w": 1, "h": 1, "col": 2, "row": 1},
+			{"x": 1, "y": 3, "w": 1, "h": 1, "col": 0, "row": 2},
+			{"x": 2, "y": 3, "w": 1, "h": 1, "col": 1, "row": 2},
+			{"x": 3, "y": 3, "w": 1, "h": 1, "col": 2, "row": 2}
+		],
+		"spawns": {"X": {"x": 4.5, "y": 1.5}},
+		"sprites": [{"type": "light", "x": 1.5, "y": 2.5}]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cx, cy, ok := m.BoardCellAt(Vec2{X: 2.2, Y: 3.7}); !ok || cx != 1 || cy != 2 {
+		t.Errorf("BoardCellAt = %d, %d, %v, want the room cell 1, 2", cx, cy, ok)
+	}
+	if _, _, ok := m.BoardCellAt(Vec2{X: 4.5, Y: 1.5}); ok {
+		t.Error("a tile outside of every room should not be a board cell")
+	}
+	if got := m.BoardCellCenter(1, 2); got != (Vec2{X: 2.5, Y: 3.5}) {
+		t.Errorf("BoardCellCenter = %v, want the room center", got)
+	}
+
+	if got := m.Spawn(PlayerSymbolX); got != (Vec2{X: 4.5, Y: 1.5}) {
+		t.Errorf("spawn of X = %v", got)
+	}
+	if got := m.Spawn(PlayerSymbolO); got != defaultPlayerOSpawn() {
+		t.Errorf("spawn of O = %v, want the default one", got)
+	}
+
+	sprites := m.NewSprites(spriteTypes)
+	if len(sprites) != 1 || sprites[0].TextureID != Light || sprites[0].Position != (Vec2{X: 1.5, Y: 2.5}) {
+		t.Errorf("sprites = %+v, want one light", sprites)
+	}
+}
+
+func TestParseMap_InvalidRoomsSpawnsSprites(t *testing.T) {
+	const tiles = `"tiles": [[1,1,1,1,1],[1,0,0,0,1],[1,0,0,0,1],[1,1,1,1,1]]`
+	board := tiles + `, ` + testBoardRooms()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Room out of map", data: `{` + tiles + `, "rooms": [{"x": 3, "y": 1, "w": 3, "h": 1}]}`},
+		{name: "Empty room", data: `{` + tiles + `, "rooms": [{"x": 1, "y": 1, "w": 0, "h": 1}]}`},
+		{name: "Cell out of board", data: `{` + tiles + `, "rooms": [{"x": 1, "y": 1, "w": 1, "h": 1, "col": 3}]}`},
+		{
+			name: "Overlapping rooms",
+			data: `{` + tiles + `, "rooms": [{"x": 1, "y": 1, "w": 2, "h": 2}, {"x": 2, "y": 2, "w": 1, "h": 1, "col": 1}]}`,
+		},
+		{
+			name: "Cell twice",
+			data: `{` + tiles + `, "rooms": [{"x": 1, "y": 1, "w": 1, "h": 1}, {"x": 3, "y": 1, "w": 1, "h": 1}]}`,
+		},
+		{name: "Missing cells", data: `{` + tiles + `, "rooms": [{"x": 1, "y": 1, "w": 1, "h": 1}]}`},
+		{name: "Spawn in a wall", data: `{` + board + `, "spawns": {"O": {"x": 0.5, "y": 0.5}}}`},
+		{name: "Unknown player", data: `{` + board + `, "spawns": {"Z": {"x": 1.5, "y": 1.5}}}`},
+		{name: "Sprite out of map", data: `{` + board + `, "sprites": [{"type": "skull", "x": 9, "y": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMap([]byte(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestEncodeMap_RoundTrip(t *testing.T) {
+	m := NewMap()
+	m.Faces = map[TileCoord]FaceTextures{{X: 7, Y: 5}: {FaceWest: 2}, {X: 7, Y: 1}: {FaceNorth: 3}}
+
+	data, err := EncodeMap(m)
+	if err != nil {
+		t.Fatalf("EncodeMap: %v", err)
+	}
+
+	got, err := ParseMap(data)
+	if err != nil {
+		t.Fatalf("ParseMap of the encoded map: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("round trip changed the map:\n%s", data)
+	}
+
+	// the output does not depend on the map iteration order
+	again, _ := EncodeMap(got)
+	if !bytes.Equal(again, data) {
+		t.Errorf("encoding the same map twice differs:\n%s\n%s", data, again)
+	}
+}
+
+func TestMap_LegacyBoardCells(t *testing.T) {
+	m := NewMap()
+	m.Rooms = nil
+
+	pos := Vec2{X: 20.5, Y: 15.2}
+	cx, cy, ok := m.BoardCellAt(pos)
+	wx, wy, wok := boardCellAt(pos)
+	if cx != wx || cy != wy || ok != wok {
+		t.Errorf("BoardCellAt = %d, %d, %v, want the legacy grid %d, %d, %v", cx, cy, ok, wx, wy, wok)
+	}
+	if got := m.BoardCellCenter(1, 2); got != (Vec2{X: 10.5, Y: 17.5}) {
+		t.Errorf("BoardCellCenter = %v, want the legacy center", got)
+	}
+}
+
+func TestNewMap_RoomsMatchLegacyGrid(t *testing.T) {
+	m := NewMap()
+	for y := range m.Height() {
+		for x := range m.Width() {
+			pos := Vec2{X: float64(x) + HalfTile, Y: float64(y) + HalfTile}
+			cx, cy, ok := m.BoardCellAt(pos)
+			wx, wy, wok := boardCellAt(pos)
+			if cx != wx || cy != wy || ok != wok {
+				t.Fatalf("tile (%d,%d) is in cell %d, %d, %v, want %d, %d, %v", x, y, cx, cy, ok, wx, wy, wok)
+			}
+		}
+	}
+}
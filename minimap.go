@@ -4,46 +4,127 @@
 package main
 
 import (
+	"image/color"
+	"math"
+	"strconv"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-type Minimap struct{}
+// Minimap draws the map seen by the current player in the top right corner, scaled to fit any map size.
+// overview draws it full screen instead, toggled with Tab.
+// layer holds the whole map drawn north up, it is composited on canvas, the minimap square,
+// centered or turned around the current player, and canvas clips it to the square.
+// With several levels of the dungeon it shows the level of the current player, unless pinned to another level.
+type Minimap struct {
+	overview bool
+	layer    *ebiten.Image
+	canvas   *ebiten.Image
+	level    int
+	pinned   bool
+}
+
+// ToggleOverview switches between the corner minimap and the full screen overview.
+func (m *Minimap) ToggleOverview() {
+	m.overview = !m.overview
+}
+
+// CycleLevel shows the level step levels away from the shown one, wrapping around.
+// Coming back to the level of the current player follows them again.
+func (m *Minimap) CycleLevel(g *Game, step int) {
+	m.level = cycleIndex(m.shownLevel(g), g.levelCount(), step)
+	m.pinned = g.currentPlayer == nil || m.level != g.currentPlayer.level
+}
+
+// shownLevel returns the level of the dungeon drawn by the minimap.
+func (m *Minimap) shownLevel(g *Game) int {
+	if m.pinned && m.level < g.levelCount() {
+		return m.level
+	}
+	if g.currentPlayer == nil {
+		return 0
+	}
+	return g.currentPlayer.level
+}
+
+// minimapView is where a map is drawn on screen: the top left corner and the size of a tile, in pixels.
+type minimapView struct {
+	x, y float64
+	cell float64
+}
+
+// toScreen returns the screen position of a world position.
+func (v minimapView) toScreen(p Vec2) (float32, float32) {
+	return float32(v.x + p.X*v.cell), float32(v.y + p.Y*v.cell)
+}
+
+// minimapCellSize returns the size of a tile in pixels so that the whole map fits in the minimap square.
+func minimapCellSize(m Map) float64 {
+	return float64(MinimapSizePixels) / float64(max(m.Width(), m.Height(), 1))
+}
+
+// minimapRotation returns the angle turning the direction dir to the top of the screen.
+func minimapRotation(dir Vec2) float64 {
+	return -math.Pi/Two - math.Atan2(dir.Y, dir.X)
+}
+
+// minimapGeoM places the north up map layer in the minimap square: centered,
+// or when rotating, turned around the player position so that they look up.
+func minimapGeoM(m Map, cell float64, rotate bool, p *Player) ebiten.GeoM {
+	var geo ebiten.GeoM
+	half := float64(MinimapSizePixels) / Two
+
+	if !rotate || p == nil {
+		geo.Translate(half-float64(m.Width())*cell/Two, half-float64(m.Height())*cell/Two)
+		return geo
+	}
 
-func drawPlayer(player *Player, screen *ebiten.Image) {
-	px := MinimapPosX + player.pos.X*MinimapGridCellSize
-	py := MinimapPosY + player.pos.Y*MinimapGridCellSize
+	geo.Translate(-p.pos.X*cell, -p.pos.Y*cell)
+	geo.Rotate(minimapRotation(p.dir))
+	geo.Translate(half, half)
+	return geo
+}
 
-	col := ColorMinimapWall
-	switch player.symbol {
+// minimapPlayerColor returns the color of the player of the symbol on the minimap.
+func minimapPlayerColor(symbol PlayerSymbol) color.RGBA {
+	switch symbol {
 	case PlayerSymbolX:
-		col = ColorMinimapPlayerX
+		return ColorMinimapPlayerX
 	case PlayerSymbolO:
-		col = ColorMinimapPlayerO
+		return ColorMinimapPlayerO
 	case PlayerSymbolNone:
+	}
+	return ColorMinimapWall
+}
+
+func drawPlayer(screen *ebiten.Image, v minimapView, player *Player) {
+	if player.symbol == PlayerSymbolNone {
 		return
 	}
+	px, py := v.toScreen(player.pos)
+	col := minimapPlayerColor(player.symbol)
 
 	vector.FillRect(
 		screen,
-		float32(px-MinimapPlayerRadius),
-		float32(py-MinimapPlayerRadius),
+		px-MinimapPlayerRadius,
+		py-MinimapPlayerRadius,
 		float32(MinimapPlayerDiameter),
 		float32(MinimapPlayerDiameter),
 		col,
 		false,
 	)
 
-	endX := px + player.dir.X*(MinimapPlayerArrowLength)
-	endY := py + player.dir.Y*(MinimapPlayerArrowLength)
+	endX := px + float32(player.dir.X*MinimapPlayerArrowLength)
+	endY := py + float32(player.dir.Y*MinimapPlayerArrowLength)
 
 	// main line
 	vector.StrokeLine(
 		screen,
-		float32(px),
-		float32(py),
-		float32(endX),
-		float32(endY),
+		px,
+		py,
+		endX,
+		endY,
 		MinimapPlayerArrowWidth,
 		col,
 		true,
@@ -51,35 +132,241 @@ func drawPlayer(player *Player, screen *ebiten.Image) {
 }
 
 func (m *Minimap) Draw(screen *ebiten.Image, g *Game) {
+	if m.overview {
+		m.drawOverview(screen, g)
+		return
+	}
+
+	level := m.shownLevel(g)
+	levelMap := g.levelMap(level)
+	if !g.settings.ShowMinimap || levelMap.Width() == 0 {
+		return
+	}
+
+	cell := minimapCellSize(levelMap)
+	w := int(math.Ceil(float64(levelMap.Width()) * cell))
+	h := int(math.Ceil(float64(levelMap.Height()) * cell))
+	if m.layer == nil || m.layer.Bounds().Dx() != w || m.layer.Bounds().Dy() != h {
+		m.layer = ebiten.NewImage(w, h)
+	}
+	if m.canvas == nil {
+		m.canvas = ebiten.NewImage(MinimapSizePixels, MinimapSizePixels)
+	}
+
+	m.layer.Clear()
+	drawMinimapContent(m.layer, g, minimapView{x: 0, y: 0, cell: cell}, level)
+
+	m.canvas.Fill(ColorMinimapBorder)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM = minimapGeoM(levelMap, cell, g.settings.MinimapRotate, g.currentPlayer)
+	m.canvas.DrawImage(m.layer, op)
+
 	vector.FillRect(
 		screen,
-		float32(MinimapPosX),
+		float32(MinimapPosX-MinimapBorderWidth),
 		float32(MinimapPosY-MinimapBorderWidth),
-		float32(MinimapWidth+2*MinimapBorderWidth),
-		float32(MinimapHeight+2*MinimapBorderWidth),
+		float32(MinimapSizePixels+2*MinimapBorderWidth),
+		float32(MinimapSizePixels+2*MinimapBorderWidth),
 		ColorMinimapBorder,
 		false,
 	)
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(MinimapPosX, MinimapPosY)
+	screen.DrawImage(m.canvas, op)
 
-	mapHCells := g.worldMap.Height()
-	mapWCells := g.worldMap.Width()
-
-	for y := range mapHCells {
-		for x := range mapWCells {
-			if g.worldMap.Tiles[y][x] >= MinimapWallValue {
-				vector.FillRect(
-					screen,
-					float32(MinimapPosX+float64(x)*MinimapGridCellSize),
-					float32(MinimapPosY+float64(y)*MinimapGridCellSize),
-					float32(MinimapGridCellSize), float32(MinimapGridCellSize),
-					ColorMinimapWall,
-					false,
-				)
+	drawLevelSelector(screen, g, MinimapPosX-MinimapBorderWidth-MinimapLevelTabGap-MinimapLevelTabSize,
+		MinimapPosY, level)
+}
+
+// drawOverview draws the explored map as large as the screen allows over a dark background.
+func (m *Minimap) drawOverview(screen *ebiten.Image, g *Game) {
+	vector.FillRect(screen, 0, 0, float32(WindowSizeX), float32(WindowSizeY), ColorOverviewBackground, false)
+
+	level := m.shownLevel(g)
+	x, y, cell := fullScreenMapLayout(g.levelMap(level))
+	drawMinimapContent(screen, g, minimapView{x: x, y: y, cell: cell}, level)
+	drawLevelSelector(screen, g, MapScreenMarginPixels, MapScreenMarginPixels, level)
+
+	status := "Tab: close the map"
+	if g.levelCount() > 1 {
+		status += "  L: change level"
+	}
+	g.drawText(screen, status, MapScreenMarginPixels, float64(WindowSizeY-MapScreenStatusHeightPixels), ColorHUDText)
+}
+
+// drawLevelSelector draws a tab per level of the dungeon from the top level down, starting at (x, y),
+// with the shown level filled and a dot for each player standing on a level. A single level draws nothing.
+func drawLevelSelector(screen *ebiten.Image, g *Game, x, y float64, shown int) {
+	count := g.levelCount()
+	if count <= 1 {
+		return
+	}
+
+	for i := range count {
+		level := count - 1 - i
+		tabY := y + float64(i*(MinimapLevelTabSize+MinimapLevelTabGap))
+
+		col := ColorMinimapLevelTab
+		if level == shown {
+			col = ColorMinimapLevel
+		}
+		vector.FillRect(screen, float32(x), float32(tabY), MinimapLevelTabSize, MinimapLevelTabSize, col, false)
+		vector.StrokeRect(screen, float32(x), float32(tabY), MinimapLevelTabSize, MinimapLevelTabSize,
+			MinimapBorderWidth, ColorMinimapBorder, false)
+		g.drawTextWithFace(screen, strconv.Itoa(level+1), x+MinimapLevelTabSize/Two, tabY+MinimapLevelTabSize/Two,
+			Center, ColorHUDText, g.assets.NormalTextFace, TextLineSpacing)
+
+		for j, p := range []*Player{g.playerX, g.playerO} {
+			if p == nil || p.level != level {
+				continue
+			}
+			dotX := x - float64((j+1)*MinimapPlayerDiameter*Two)
+			dotY := tabY + MinimapLevelTabSize/Two - MinimapPlayerRadius
+			vector.FillRect(screen, float32(dotX), float32(dotY), MinimapPlayerDiameter, MinimapPlayerDiameter,
+				minimapPlayerColor(p.symbol), false)
+		}
+	}
+}
+
+// drawMinimapContent draws everything the minimap shows of the level, north up: the explored map,
+// the decorations, the winning line, the debug rays, the view cone and the players.
+func drawMinimapContent(screen *ebiten.Image, g *Game, v minimapView, level int) {
+	drawExploredMap(screen, g, v, level)
+	drawMinimapSprites(screen, g, v, level)
+
+	if g.winLine != nil {
+		drawWinningLine(screen, g.levelMap(level), v, g.winLine)
+	}
+
+	// rays cast by the world this frame and the view cone, only on the level of the current player
+	if g.currentPlayer != nil && g.currentPlayer.level == level {
+		g.debug.drawRayFan(screen, v, g.currentPlayer)
+		drawFOVCone(screen, v, g.currentPlayer, GetK(g.settings.FOV))
+	}
+	drawMinimapPlayers(screen, g, v, level)
+}
+
+// drawExploredMap draws the tiles of the level seen by the current player, every tile when the fog is disabled,
+// and the claimed rooms with their mark when enabled in the settings.
+// The levels share their layout, so the tiles seen on one level are known on every level.
+func drawExploredMap(screen *ebiten.Image, g *Game, v minimapView, level int) {
+	for y, row := range g.levelMap(level).Tiles {
+		for x, tile := range row {
+			if !minimapTileVisible(g, x, y) {
+				continue
 			}
+
+			col := ColorMinimapFloor
+			if tile >= MinimapWallValue {
+				col = ColorMinimapWall
+			}
+			sx, sy := v.toScreen(Vec2{X: float64(x), Y: float64(y)})
+			vector.FillRect(screen, sx, sy, float32(v.cell), float32(v.cell), col, false)
 		}
 	}
 
-	// draw each player
-	drawPlayer(g.playerX, screen)
-	drawPlayer(g.playerO, screen)
+	if g.settings.MinimapMarks {
+		drawClaimedRooms(screen, g, v, level)
+	}
+}
+
+// drawClaimedRooms tints the room of every claimed board cell of the level with the color of its player
+// and draws the mark.
+func drawClaimedRooms(screen *ebiten.Image, g *Game, v minimapView, level int) {
+	for cy, row := range g.board[level] {
+		for cx, symbol := range row {
+			if symbol == PlayerSymbolNone {
+				continue
+			}
+
+			col := ColorMinimapClaimedX
+			if symbol == PlayerSymbolO {
+				col = ColorMinimapClaimedO
+			}
+
+			room := g.levelMap(level).BoardCellRoom(cx, cy)
+			sx, sy := v.toScreen(Vec2{X: float64(room.X), Y: float64(room.Y)})
+			w, h := float32(float64(room.W)*v.cell), float32(float64(room.H)*v.cell)
+			vector.FillRect(screen, sx, sy, w, h, col, false)
+
+			size := float64(min(room.W, room.H)) * v.cell * MinimapMarkScale
+			mx, my := v.toScreen(room.Center())
+			drawTextureIcon(screen, g.assets.Textures[symbol.MarkTextureID()],
+				float64(mx)-size/Two, float64(my)-size/Two, size, col)
+		}
+	}
+}
+
+// minimapTileVisible returns true if the tile is shown: the fog is disabled or the current player saw it.
+func minimapTileVisible(g *Game, x, y int) bool {
+	return !g.settings.MinimapFog || g.currentPlayer == nil || g.currentPlayer.explored.Seen(x, y)
+}
+
+// drawMinimapSprites draws the decorations of the level standing on visible tiles,
+// the marks are drawn with their room.
+func drawMinimapSprites(screen *ebiten.Image, g *Game, v minimapView, level int) {
+	size := v.cell * MinimapSpriteScale
+	for _, s := range g.sprites {
+		if s.Hidden || s.Level != level || isMarkTexture(s.TextureID) ||
+			!minimapTileVisible(g, int(math.Floor(s.Position.X)), int(math.Floor(s.Position.Y))) {
+			continue
+		}
+		sx, sy := v.toScreen(s.Position)
+		drawTextureIcon(screen, g.assets.Textures[s.TextureID],
+			float64(sx)-size/Two, float64(sy)-size/Two, size, ColorMinimapWall)
+	}
+}
+
+// drawWinningLine draws a line across the rooms of the winning cells, seen from above.
+// A line going straight up through the levels of the dungeon is a dot on its room.
+func drawWinningLine(screen *ebiten.Image, m Map, v minimapView, line []BoardCell) {
+	first, last := line[0], line[len(line)-1]
+	x0, y0 := v.toScreen(m.BoardCellCenter(first.Col, first.Row))
+	x1, y1 := v.toScreen(m.BoardCellCenter(last.Col, last.Row))
+	width := float32(max(MinimapWinLineWidth, v.cell/Two))
+	if x0 == x1 && y0 == y1 {
+		vector.FillCircle(screen, x0, y0, width, ColorMinimapWinLine, true)
+		return
+	}
+	vector.StrokeLine(screen, x0, y0, x1, y1, width, ColorMinimapWinLine, true)
+}
+
+// drawFOVCone draws the edges of the field of view of the player, MinimapFOVConeLength tiles long.
+func drawFOVCone(screen *ebiten.Image, v minimapView, p *Player, fovScale float64) {
+	px, py := v.toScreen(p.pos)
+	lx, ly := v.toScreen(p.pos.Add(GetRayDirection(p.dir, fovScale, -1).Normalize().Scale(MinimapFOVConeLength)))
+	rx, ry := v.toScreen(p.pos.Add(GetRayDirection(p.dir, fovScale, 1).Normalize().Scale(MinimapFOVConeLength)))
+
+	vector.StrokeLine(screen, px, py, lx, ly, 1, ColorMinimapFOV, true)
+	vector.StrokeLine(screen, px, py, rx, ry, 1, ColorMinimapFOV, true)
+	vector.StrokeLine(screen, lx, ly, rx, ry, 1, ColorMinimapFOV, true)
+}
+
+// drawMinimapPlayers draws the players standing on the level: the current player,
+// and the other one when it stands on a tile the current player saw.
+func drawMinimapPlayers(screen *ebiten.Image, g *Game, v minimapView, level int) {
+	for _, p := range []*Player{g.playerX, g.playerO} {
+		if p.level != level {
+			continue
+		}
+		if p != g.currentPlayer && !minimapTileVisible(g, int(math.Floor(p.pos.X)), int(math.Floor(p.pos.Y))) {
+			continue
+		}
+		drawPlayer(screen, v, p)
+	}
+}
+
+// fullScreenMapLayout returns the screen position of the top left corner of the map and the size of a tile,
+// the largest that fits the map above a status bar, centered horizontally.
+func fullScreenMapLayout(m Map) (float64, float64, float64) {
+	if m.Width() == 0 || m.Height() == 0 {
+		return MapScreenMarginPixels, MapScreenMarginPixels, 0
+	}
+
+	availW := float64(WindowSizeX - Two*MapScreenMarginPixels)
+	availH := float64(WindowSizeY - Two*MapScreenMarginPixels - MapScreenStatusHeightPixels)
+	tile := math.Min(availW/float64(m.Width()), availH/float64(m.Height()))
+
+	originX := (float64(WindowSizeX) - tile*float64(m.Width())) / Two
+	return originX, MapScreenMarginPixels, tile
 }
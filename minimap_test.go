@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinimapRotation(t *testing.T) {
+	for _, dir := range []Vec2{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 0, Y: -1}, {X: 0.6, Y: -0.8}} {
+		up := dir.Rotate(minimapRotation(dir))
+		if math.Abs(up.X) > 1e-9 || math.Abs(up.Y+1) > 1e-9 {
+			t.Errorf("direction %v rotated to %v, want it pointing up", dir, up)
+		}
+	}
+}
+
+func TestMinimapCellSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{name: "square", width: 22, height: 22},
+		{name: "wide", width: 40, height: 10},
+		{name: "tall", width: 8, height: 64},
+		{name: "tiny", width: 3, height: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Map{Tiles: make([][]TileID, tt.height)}
+			for y := range m.Tiles {
+				m.Tiles[y] = make([]TileID, tt.width)
+			}
+
+			cell := minimapCellSize(m)
+			longest := float64(max(tt.width, tt.height)) * cell
+			if math.Abs(longest-MinimapSizePixels) > 1e-9 {
+				t.Errorf("the longest side is %v pixels, want %v", longest, MinimapSizePixels)
+			}
+		})
+	}
+
+	if cell := minimapCellSize(Map{}); math.IsInf(cell, 0) || math.IsNaN(cell) {
+		t.Errorf("an empty map should not divide by zero, got %v", cell)
+	}
+}
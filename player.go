@@ -16,53 +16,118 @@ const (
 	PlayerSymbolO
 )
 
+// MarkTextureID returns the texture of the mark placed on the board for the symbol.
+func (s PlayerSymbol) MarkTextureID() TextureID {
+	if s == PlayerSymbolO {
+		return PlayerOSymbol
+	}
+	return PlayerXSymbol
+}
+
+// Opponent returns the symbol of the other player, PlayerSymbolNone for an empty cell.
+func (s PlayerSymbol) Opponent() PlayerSymbol {
+	switch s {
+	case PlayerSymbolX:
+		return PlayerSymbolO
+	case PlayerSymbolO:
+		return PlayerSymbolX
+	case PlayerSymbolNone:
+	}
+	return PlayerSymbolNone
+}
+
+// String returns "X", "O" or "-" for an empty cell.
+func (s PlayerSymbol) String() string {
+	switch s {
+	case PlayerSymbolX:
+		return "X"
+	case PlayerSymbolO:
+		return "O"
+	case PlayerSymbolNone:
+		return "-"
+	}
+	return "?"
+}
+
 // Player represents a player in the game.
 // pos is the player's position in the world.
 // dir is the player's direction vector.
 // symbol is the player's symbol (X or O).
 // name is the player's name.
-// score is the player's score.
+// score is the player's score, the quantum mode gives half points.
+// moveSpeed, rotSpeed and speedMultiplier come from the settings.
+// noclip lets the player walk through walls, it is toggled from the console.
+// anim is the character animation seen by the other player, walking or idle.
+// explored holds the tiles the player has seen, revealed on the minimap.
+// mark is the symbol the player draws next, their own unless the game mode lets them choose another one.
+// level is the level of the dungeon the player stands on, 0 for the ground.
 type Player struct {
 	pos                Vec2
 	dir                Vec2
 	symbol             PlayerSymbol
-	symbolTextureID    TextureID
 	characterTextureID TextureID
 	name               string
-	score              int
+	score              float64
+	moveSpeed          float64
+	rotSpeed           float64
+	speedMultiplier    float64
+	noclip             bool
+	anim               AnimationState
+	explored           *Exploration
+	mark               PlayerSymbol
+	level              int
 }
 
 // NewPlayer creates a new player with the given position, symbol, and name.
 func NewPlayer(x, y float64, symbol PlayerSymbol, name string) *Player {
-	symbolTextureID := PlayerXSymbol
 	characterTextureID := PlayerXCharacter
 
 	if symbol == PlayerSymbolO {
-		symbolTextureID = PlayerOSymbol
 		characterTextureID = PlayerOCharacter
 	}
 	return &Player{
 		pos:                Vec2{x, y},
-		dir:                Vec2{-1, 0},
+		dir:                defaultPlayerDir(),
 		symbol:             symbol,
-		symbolTextureID:    symbolTextureID,
 		characterTextureID: characterTextureID,
 		name:               name,
 		score:              0,
+		moveSpeed:          PlayerMovementSpeed,
+		rotSpeed:           PlayerRotationSpeed,
+		speedMultiplier:    PlayerMovementSpeedMultiplicator,
+		anim:               NewAnimationState(&AnimIdle, 0),
+		explored:           &Exploration{width: 0, height: 0, seen: nil},
+		mark:               symbol,
+		level:              0,
 	}
 }
 
+// applySettings updates the player speeds from the settings.
+func (p *Player) applySettings(s Settings) {
+	p.moveSpeed = s.MovementSpeed
+	p.rotSpeed = s.RotationSpeed
+	p.speedMultiplier = s.SpeedMultiplier
+}
+
 func (p *Player) Update(g *Game) {
-	// only update if this is the current player
-	if g.currentPlayer != p {
+	// the keyboard belongs to the map editor
+	if g.state == StateEditor {
 		return
 	}
 
-	moveSpeed := PlayerMovementSpeed * DeltaTime
-	rotSpeed := PlayerRotationSpeed * DeltaTime
+	// only update if this is the current player, the other one stands still, and both wait for the camera
+	if g.currentPlayer != p || g.state == StateTurnTransition {
+		p.animate(false)
+		return
+	}
+
+	start := p.pos
+
+	moveSpeed := p.moveSpeed * DeltaTime
+	rotSpeed := p.rotSpeed * DeltaTime
 
 	if ebiten.IsKeyPressed(ebiten.KeyShift) {
-		moveSpeed *= PlayerMovementSpeedMultiplicator
+		moveSpeed *= p.speedMultiplier
 	}
 
 	// w/s for forward/backward
@@ -80,23 +145,48 @@ func (p *Player) Update(g *Game) {
 	if ebiten.IsKeyPressed(ebiten.KeyA) {
 		p.rotate(-rotSpeed)
 	}
+
+	p.animate(p.pos != start)
+
+	// what the player sees is revealed on the minimap
+	p.explored.Look(g.levelMap(p.level), p.pos, p.dir, GetK(g.settings.FOV))
+}
+
+// animate plays the walk cycle while the player moves and the idle animation otherwise.
+func (p *Player) animate(moving bool) {
+	if moving {
+		p.anim.Play(&AnimWalk)
+	} else {
+		p.anim.Play(&AnimIdle)
+	}
+	p.anim.Advance(DeltaTime)
 }
 
 // Move the player by the given velocity vector, checking for collisions.
 func (p *Player) move(g *Game, velocity Vec2) {
 	// try to move in X
 	nextPos := p.pos.Add(Vec2{X: velocity.X, Y: 0})
-	if g.worldMap.IsWalkable(nextPos) {
+	if p.canMoveTo(g, nextPos) {
 		p.pos = nextPos
 	}
 
 	// try to move in Y
 	nextPos = p.pos.Add(Vec2{X: 0, Y: velocity.Y})
-	if g.worldMap.IsWalkable(nextPos) {
+	if p.canMoveTo(g, nextPos) {
 		p.pos = nextPos
 	}
 }
 
+// canMoveTo returns true if the player may stand at pos.
+// with noclip walls are ignored but the player still stays inside the map.
+func (p *Player) canMoveTo(g *Game, pos Vec2) bool {
+	m := g.levelMap(p.level)
+	if p.noclip {
+		return m.Contains(pos)
+	}
+	return m.IsWalkable(pos)
+}
+
 // Rotate the player by the given angle in radians.
 func (p *Player) rotate(angle float64) {
 	p.dir = p.dir.Rotate(angle)
@@ -0,0 +1,386 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// SpookyMark is a mark of the quantum mode in superposition, in two cells of the flat board at once
+// until a measurement collapses it into one of them. Turn is the number of the move that placed it, its subscript.
+type SpookyMark struct {
+	Player PlayerSymbol
+	Turn   int
+	Cells  [2]BoardCell
+}
+
+// otherCell returns the cell of the mark that is not c, ok is false when the mark is not in c.
+func (s SpookyMark) otherCell(c BoardCell) (BoardCell, bool) {
+	switch c {
+	case s.Cells[0]:
+		return s.Cells[1], true
+	case s.Cells[1]:
+		return s.Cells[0], true
+	}
+	return BoardCell{}, false
+}
+
+// QuantumBoard is the board of the quantum mode beside the classical marks of the Board.
+// Spooky are the marks still in superposition, the edges of the entanglement graph between the cells.
+// Subscripts holds the turn of the classical mark of each cell, 0 for an empty cell, and Moves the number of moves
+// played so far. Cycle is the turn of the spooky mark that closed a cycle of the graph and waits to be collapsed,
+// 0 when none does.
+type QuantumBoard struct {
+	Spooky     []SpookyMark
+	Subscripts [GridSize][GridSize]int
+	Moves      int
+	Cycle      int
+}
+
+var errNoCycle = errors.New("no cycle to collapse")
+
+// Clone returns a copy of the board that does not share its spooky marks.
+func (q *QuantumBoard) Clone() QuantumBoard {
+	clone := *q
+	clone.Spooky = slices.Clone(q.Spooky)
+	return clone
+}
+
+// mark returns the spooky mark placed on the turn, ok is false when it is not in superposition.
+func (q *QuantumBoard) mark(turn int) (SpookyMark, bool) {
+	i := slices.IndexFunc(q.Spooky, func(s SpookyMark) bool { return s.Turn == turn })
+	if i < 0 {
+		return SpookyMark{}, false
+	}
+	return q.Spooky[i], true
+}
+
+// CycleMark returns the spooky mark that closed a cycle and waits to be collapsed, ok is false when there is none.
+func (q *QuantumBoard) CycleMark() (SpookyMark, bool) {
+	return q.mark(q.Cycle)
+}
+
+// MarksIn returns the spooky marks in the cell, in the order they were placed.
+func (q *QuantumBoard) MarksIn(c BoardCell) []SpookyMark {
+	var marks []SpookyMark
+	for _, s := range q.Spooky {
+		if _, ok := s.otherCell(c); ok {
+			marks = append(marks, s)
+		}
+	}
+	return marks
+}
+
+// connected returns true if a path of spooky marks joins the cells in the entanglement graph.
+func (q *QuantumBoard) connected(a, b BoardCell) bool {
+	seen := map[BoardCell]bool{a: true}
+	queue := []BoardCell{a}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == b {
+			return true
+		}
+		for _, s := range q.Spooky {
+			if next, ok := s.otherCell(c); ok && !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// PlaceSpooky adds a spooky mark of the player in both cells as the next move. It returns true when the mark
+// closes a cycle of the entanglement graph, the mark then waits to be collapsed.
+func (q *QuantumBoard) PlaceSpooky(player PlayerSymbol, a, b BoardCell) bool {
+	closes := q.connected(a, b)
+	q.Moves++
+	q.Spooky = append(q.Spooky, SpookyMark{Player: player, Turn: q.Moves, Cells: [2]BoardCell{a, b}})
+	if closes {
+		q.Cycle = q.Moves
+	}
+	return closes
+}
+
+// PlaceClassic draws a classical mark of the player on the empty cell of the board as the next move,
+// the last move of a round when a single cell is left.
+func (q *QuantumBoard) PlaceClassic(b *Board, player PlayerSymbol, c BoardCell) {
+	q.Moves++
+	b[c.Row][c.Col] = player
+	q.Subscripts[c.Row][c.Col] = q.Moves
+}
+
+// Collapse measures the cycle waiting to be collapsed: the spooky mark closing it becomes a classical mark in the cell,
+// then every other spooky mark in a collapsed cell is pushed into its other cell, until the whole cycle and the marks
+// hanging on it are classical marks on the board.
+func (q *QuantumBoard) Collapse(b *Board, c BoardCell) error {
+	closing, ok := q.CycleMark()
+	if !ok {
+		return errNoCycle
+	}
+	if _, in := closing.otherCell(c); !in {
+		return fmt.Errorf("%w: mark %d is not in cell (%d,%d)", errIllegalMove, closing.Turn, c.Row, c.Col)
+	}
+	q.Cycle = 0
+
+	type measure struct {
+		turn int
+		cell BoardCell
+	}
+	queue := []measure{{turn: closing.Turn, cell: c}}
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		i := slices.IndexFunc(q.Spooky, func(s SpookyMark) bool { return s.Turn == m.turn })
+		if i < 0 || b[m.cell.Row][m.cell.Col] != PlayerSymbolNone {
+			continue
+		}
+		s := q.Spooky[i]
+		q.Spooky = slices.Delete(q.Spooky, i, i+1)
+		b[m.cell.Row][m.cell.Col] = s.Player
+		q.Subscripts[m.cell.Row][m.cell.Col] = s.Turn
+
+		for _, other := range q.MarksIn(m.cell) {
+			next, _ := other.otherCell(m.cell)
+			queue = append(queue, measure{turn: other.Turn, cell: next})
+		}
+	}
+	return nil
+}
+
+// bestLine returns the line of classical marks of the player completed first, the one whose highest subscript
+// is the lowest, and that subscript. ok is false when the player filled no line.
+func (q *QuantumBoard) bestLine(b *Board, player PlayerSymbol) ([]BoardCell, int, bool) {
+	var best []BoardCell
+	bestTurn := 0
+	for _, line := range boardLines() {
+		full := true
+		turn := 0
+		for _, c := range line {
+			full = full && b[c.Row][c.Col] == player
+			turn = max(turn, q.Subscripts[c.Row][c.Col])
+		}
+		if full && (best == nil || turn < bestTurn) {
+			best, bestTurn = line[:], turn
+		}
+	}
+	return best, bestTurn, best != nil
+}
+
+// spookyMove returns the move of the player drawing a spooky mark in both cells, the first cell in reading order
+// so each pair of cells is a single move.
+func spookyMove(player PlayerSymbol, a, b BoardCell) Move {
+	if b.Row*GridSize+b.Col < a.Row*GridSize+a.Col {
+		a, b = b, a
+	}
+	return Move{Kind: MoveSpooky, Cell: a, Pair: b, Player: player, Mark: player}
+}
+
+// QuantumMode is the quantum tic-tac-toe: each move places a spooky mark of the player in two empty cells at once,
+// subscripted with the number of the move. When a mark closes a cycle of marks linked by their cells,
+// the other player chooses which of its two cells it collapses into, and the marks of the cycle follow as classical
+// marks before that player moves. A player filling a line of classical marks wins a point, and when both do at once
+// the line with the lowest highest subscript wins the point and the other one half a point.
+// The last empty cell is played classically and a full board is a draw.
+// The spooky marks are on the quantum board of the game, the classical ones on the first level of the board.
+type QuantumMode struct {
+	ClassicMode
+
+	board *QuantumBoard
+}
+
+var errNoQuantumBoard = errors.New("the quantum mode has no quantum board")
+
+// Name returns "Quantum".
+func (QuantumMode) Name() string {
+	return "Quantum"
+}
+
+// quantum returns the quantum board the mode plays on, an empty one when the game did not give it one.
+func (q QuantumMode) quantum() *QuantumBoard {
+	if q.board == nil {
+		return &QuantumBoard{}
+	}
+	return q.board
+}
+
+// fork returns the mode playing on a copy of its quantum board.
+func (q QuantumMode) fork() GameMode {
+	clone := q.quantum().Clone()
+	return QuantumMode{ClassicMode: q.ClassicMode, board: &clone}
+}
+
+// LegalMoves returns the collapses of the waiting cycle into either cell of its mark, or else a spooky mark
+// on every pair of empty cells, or the classical mark on the last empty cell.
+func (q QuantumMode) LegalMoves(b *Cube, player PlayerSymbol) []Move {
+	if player == PlayerSymbolNone {
+		return nil
+	}
+	if winner, _ := b[0].CheckWinner(); winner != PlayerSymbolNone {
+		return nil
+	}
+
+	if closing, ok := q.quantum().CycleMark(); ok {
+		moves := make([]Move, 0, len(closing.Cells))
+		for _, c := range closing.Cells {
+			moves = append(moves, Move{Kind: MoveCollapse, Cell: c, Pair: BoardCell{}, Player: player, Mark: player})
+		}
+		return moves
+	}
+
+	empty := emptyCellMoves(b, 1, player, q.Marks(player))
+	if len(empty) <= 1 {
+		return empty
+	}
+	var moves []Move
+	for i, first := range empty {
+		for _, second := range empty[i+1:] {
+			moves = append(moves, spookyMove(player, first.Cell, second.Cell))
+		}
+	}
+	return moves
+}
+
+// Apply places the spooky mark, the classical mark or collapses the cycle.
+func (q QuantumMode) Apply(b *Cube, m Move) error {
+	if q.board == nil {
+		return errNoQuantumBoard
+	}
+	if !isLegalMove(q, b, m) {
+		return fmt.Errorf("%w: %s on cell (%d,%d)", errIllegalMove, m.Mark, m.Cell.Row, m.Cell.Col)
+	}
+
+	switch m.Kind {
+	case MovePlace:
+		q.board.PlaceClassic(&b[0], m.Mark, m.Cell)
+	case MoveSpooky:
+		q.board.PlaceSpooky(m.Mark, m.Cell, m.Pair)
+	case MoveCollapse:
+		return q.board.Collapse(&b[0], m.Cell)
+	}
+	return nil
+}
+
+// Result returns the owner of the line of classical marks completed first as the winner, with the other player
+// as second when they filled a line too, or a draw when the board is full.
+func (q QuantumMode) Result(b *Cube, _ Move) RoundResult {
+	r := RoundResult{Over: b[0].IsFull(), Winner: PlayerSymbolNone, Line: nil, Second: PlayerSymbolNone}
+	first := 0
+	for _, player := range []PlayerSymbol{PlayerSymbolX, PlayerSymbolO} {
+		line, turn, ok := q.quantum().bestLine(&b[0], player)
+		if !ok {
+			continue
+		}
+
+		r.Over = true
+		switch {
+		case r.Winner == PlayerSymbolNone:
+			r.Winner, r.Line, first = player, line, turn
+		case turn < first:
+			r.Second = r.Winner
+			r.Winner, r.Line, first = player, line, turn
+		default:
+			r.Second = player
+		}
+	}
+	return r
+}
+
+// Score gives a point to the winner and half a point to the second.
+func (q QuantumMode) Score(r RoundResult, player PlayerSymbol) float64 {
+	if r.Over && r.Second == player && player != PlayerSymbolNone {
+		return QuantumSecondPoints
+	}
+	return q.ClassicMode.Score(r, player)
+}
+
+// collapseChoices returns the collapse moves the current player chooses from, in the order of the keys 1 and 2,
+// none unless a cycle waits to be collapsed.
+func (g *Game) collapseChoices() []Move {
+	if g.currentPlayer == nil {
+		return nil
+	}
+
+	var choices []Move
+	for _, m := range g.gameMode().LegalMoves(&g.board, g.currentPlayer.symbol) {
+		if m.Kind == MoveCollapse {
+			choices = append(choices, m)
+		}
+	}
+	return choices
+}
+
+// markSubscript returns the subscript of the classical mark of the cell in the quantum mode, 0 in the other modes.
+func (g *Game) markSubscript(cell BoardCell) int {
+	if cell.Level != 0 {
+		return 0
+	}
+	return g.quantum.Subscripts[cell.Row][cell.Col]
+}
+
+// spawnQuantumMarks replaces the mark sprites of the ground level by the marks of the quantum mode:
+// the classical marks in the center of their room, and the spooky marks faded around it, each one with its subscript.
+func (g *Game) spawnQuantumMarks() {
+	filtered := g.sprites[:0]
+	for _, s := range g.sprites {
+		if s.Level != 0 || !isMarkTexture(s.TextureID) {
+			filtered = append(filtered, s)
+		}
+	}
+	g.sprites = filtered
+
+	for cy, row := range g.board[0] {
+		for cx, symbol := range row {
+			cell := BoardCell{Col: cx, Row: cy, Level: 0}
+			center := g.worldMap.BoardCellCenter(cx, cy)
+			if symbol != PlayerSymbolNone {
+				g.sprites = append(g.sprites, &Sprite{
+					Position:  center,
+					TextureID: symbol.MarkTextureID(),
+					Scale:     1.0,
+					Z:         0.0,
+					Hidden:    false,
+					Level:     0,
+					Subscript: g.markSubscript(cell),
+				})
+				continue
+			}
+
+			// the spooky marks of the room stand in a ring around its center
+			marks := g.quantum.MarksIn(cell)
+			for i, s := range marks {
+				angle := Two * math.Pi * float64(i) / float64(len(marks))
+				offset := Vec2{X: math.Cos(angle), Y: math.Sin(angle)}.Scale(QuantumSpookySpread)
+				g.sprites = append(g.sprites, &Sprite{
+					Position:  center.Add(offset),
+					TextureID: s.Player.MarkTextureID(),
+					Scale:     QuantumSpookyScale,
+					Z:         0.0,
+					Hidden:    false,
+					Level:     0,
+					Fade:      QuantumSpookyFade,
+					Subscript: s.Turn,
+				})
+			}
+		}
+	}
+}
+
+// collapsedCells returns the cells of the flat board empty before and taken after, the marks a collapse made classical.
+func collapsedCells(before, after Board) []BoardCell {
+	var cells []BoardCell
+	for cy, row := range after {
+		for cx, symbol := range row {
+			if symbol != PlayerSymbolNone && before[cy][cx] == PlayerSymbolNone {
+				cells = append(cells, BoardCell{Col: cx, Row: cy, Level: 0})
+			}
+		}
+	}
+	return cells
+}
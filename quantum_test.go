@@ -0,0 +1,200 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func flatCell(col, row int) BoardCell {
+	return BoardCell{Col: col, Row: row, Level: 0}
+}
+
+func TestQuantumBoard_CycleAndCollapse(t *testing.T) {
+	var q QuantumBoard
+	var b Board
+
+	// X1 and O2 link the top row, X3 closes the cycle and O4 hangs on it
+	if q.PlaceSpooky(PlayerSymbolX, flatCell(0, 0), flatCell(1, 0)) ||
+		q.PlaceSpooky(PlayerSymbolO, flatCell(1, 0), flatCell(2, 0)) {
+		t.Fatal("a chain of marks is not a cycle")
+	}
+	if !q.PlaceSpooky(PlayerSymbolX, flatCell(2, 0), flatCell(0, 0)) {
+		t.Fatal("the third mark of the row closes a cycle")
+	}
+	q.PlaceSpooky(PlayerSymbolO, flatCell(2, 0), flatCell(1, 1))
+	if closing, ok := q.CycleMark(); !ok || closing.Turn != 3 {
+		t.Fatalf("CycleMark() = %v, want X3 waiting", closing)
+	}
+
+	if err := q.Collapse(&b, flatCell(1, 1)); !errors.Is(err, errIllegalMove) {
+		t.Errorf("Collapse() outside of the mark = %v, want an illegal move", err)
+	}
+	if err := q.Collapse(&b, flatCell(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// X3 in the corner pushes X1 to the middle, O2 to the right and O4 below
+	want := Board{
+		{PlayerSymbolX, PlayerSymbolX, PlayerSymbolO},
+		{PlayerSymbolNone, PlayerSymbolO, PlayerSymbolNone},
+	}
+	if b != want || len(q.Spooky) != 0 {
+		t.Errorf("board after the collapse = %v with %d spooky marks, want %v", b, len(q.Spooky), want)
+	}
+	if q.Subscripts[0][0] != 3 || q.Subscripts[0][1] != 1 || q.Subscripts[1][1] != 4 {
+		t.Errorf("subscripts = %v, want the turns of the collapsed marks", q.Subscripts)
+	}
+	if err := q.Collapse(&b, flatCell(0, 0)); !errors.Is(err, errNoCycle) {
+		t.Errorf("Collapse() without a cycle = %v", err)
+	}
+}
+
+func TestQuantumBoard_CloneDoesNotShareMarks(t *testing.T) {
+	var q QuantumBoard
+	q.PlaceSpooky(PlayerSymbolX, flatCell(0, 0), flatCell(1, 0))
+
+	clone := q.Clone()
+	clone.PlaceSpooky(PlayerSymbolO, flatCell(0, 0), flatCell(1, 0))
+	if len(q.Spooky) != 1 || q.Cycle != 0 {
+		t.Errorf("the board changed with its clone: %+v", q)
+	}
+}
+
+func TestQuantumMode_Moves(t *testing.T) {
+	var q QuantumBoard
+	mode := QuantumMode{board: &q}
+	var b Cube
+
+	// a spooky mark on every pair of cells
+	if got := mode.LegalMoves(&b, PlayerSymbolX); len(got) != 36 {
+		t.Fatalf("LegalMoves() = %d moves, want one per pair of empty cells", len(got))
+	}
+	single := spookyMove(PlayerSymbolX, flatCell(0, 0), flatCell(0, 0))
+	if err := mode.Apply(&b, single); !errors.Is(err, errIllegalMove) {
+		t.Errorf("Apply() of a mark in a single cell = %v, want an illegal move", err)
+	}
+	if err := (QuantumMode{}).Apply(&b, spookyMove(PlayerSymbolX, flatCell(0, 0), flatCell(1, 0))); err == nil {
+		t.Error("a mode without its quantum board cannot apply a move")
+	}
+
+	for _, m := range []Move{
+		spookyMove(PlayerSymbolX, flatCell(1, 0), flatCell(0, 0)),
+		spookyMove(PlayerSymbolO, flatCell(0, 0), flatCell(1, 0)),
+	} {
+		if err := mode.Apply(&b, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the other player collapses the cycle into either cell of O2
+	moves := mode.LegalMoves(&b, PlayerSymbolX)
+	if len(moves) != 2 || moves[0].Kind != MoveCollapse ||
+		moves[0].Cell != flatCell(0, 0) || moves[1].Cell != flatCell(1, 0) {
+		t.Fatalf("LegalMoves() = %v, want both collapses of O2", moves)
+	}
+	if err := mode.Apply(&b, moves[1]); err != nil {
+		t.Fatal(err)
+	}
+	if b[0][0][1] != PlayerSymbolO || b[0][0][0] != PlayerSymbolX {
+		t.Errorf("board = %v, want O2 where chosen and X1 in the other cell", b[0])
+	}
+
+	// the last empty cell is played classically
+	b[0] = Board{
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolX},
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolO},
+		{PlayerSymbolO, PlayerSymbolX, PlayerSymbolNone},
+	}
+	moves = mode.LegalMoves(&b, PlayerSymbolX)
+	if len(moves) != 1 || moves[0].Kind != MovePlace || moves[0].Cell != flatCell(2, 2) {
+		t.Errorf("LegalMoves() = %v, want the classical mark on the last cell", moves)
+	}
+}
+
+func TestQuantumMode_ResultAndScore(t *testing.T) {
+	q := QuantumBoard{Subscripts: [GridSize][GridSize]int{
+		{1, 3, 5},
+		{2, 4, 6},
+		{7, 8, 9},
+	}}
+	mode := QuantumMode{board: &q}
+
+	// X fills the top row up to move 5, O the middle one up to move 6: X first, O second
+	b := Cube{{
+		{PlayerSymbolX, PlayerSymbolX, PlayerSymbolX},
+		{PlayerSymbolO, PlayerSymbolO, PlayerSymbolO},
+	}}
+	r := mode.Result(&b, Move{})
+	if !r.Over || r.Winner != PlayerSymbolX || r.Second != PlayerSymbolO || len(r.Line) != GridSize {
+		t.Fatalf("Result() = %+v, want X first and O second", r)
+	}
+	if mode.Score(r, PlayerSymbolX) != 1 || mode.Score(r, PlayerSymbolO) != 0.5 {
+		t.Errorf("scores X %v, O %v, want 1 and a half", mode.Score(r, PlayerSymbolX), mode.Score(r, PlayerSymbolO))
+	}
+
+	// the line completed first wins, whatever the symbol
+	q.Subscripts[0][2] = 8
+	if r = mode.Result(&b, Move{}); r.Winner != PlayerSymbolO || r.Second != PlayerSymbolX {
+		t.Errorf("Result() = %+v, want O first", r)
+	}
+
+	b[0][1][1] = PlayerSymbolNone
+	if r = mode.Result(&b, Move{}); r.Winner != PlayerSymbolX || r.Second != PlayerSymbolNone {
+		t.Errorf("Result() = %+v, want X alone", r)
+	}
+}
+
+func TestGame_QuantumTurn(t *testing.T) {
+	m := NewMap()
+	pX := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	pO := NewPlayer(9.5, 1.5, PlayerSymbolO, "O")
+	g := &Game{worldMap: m, playerX: pX, playerO: pO, currentPlayer: pX, mode: QuantumMode{}, state: StatePlaying}
+
+	// the first room is picked, the second one places the spooky mark
+	if _, ok := g.moveAt(flatCell(0, 0)); ok || g.pickedCell == nil {
+		t.Fatal("the first room of a spooky mark is picked without a move")
+	}
+	move, ok := g.moveAt(flatCell(1, 0))
+	if !ok || move != spookyMove(PlayerSymbolX, flatCell(0, 0), flatCell(1, 0)) || g.pickedCell != nil {
+		t.Fatalf("moveAt() = %v, want the spooky mark in both rooms", move)
+	}
+	if err := g.playMove(move); err != nil {
+		t.Fatal(err)
+	}
+
+	faded := 0
+	for _, s := range g.sprites {
+		if isMarkTexture(s.TextureID) && s.Fade > 0 && s.Subscript == 1 {
+			faded++
+		}
+	}
+	if faded != 2 || g.currentPlayer != pO {
+		t.Fatalf("%d faded X1 marks, want one in each room and the turn to O", faded)
+	}
+
+	// O closes the cycle, X collapses it and keeps the turn
+	g.currentPlayer = pO
+	if err := g.playMove(spookyMove(PlayerSymbolO, flatCell(0, 0), flatCell(1, 0))); err != nil {
+		t.Fatal(err)
+	}
+	g.currentPlayer = pX
+	choices := g.collapseChoices()
+	if len(choices) != 2 {
+		t.Fatalf("collapseChoices() = %v, want both rooms of O2", choices)
+	}
+	if err := g.playMove(choices[0]); err != nil {
+		t.Fatal(err)
+	}
+	if g.currentPlayer != pX || g.board[0][0][0] != PlayerSymbolO || g.board[0][0][1] != PlayerSymbolX {
+		t.Errorf("board = %v, want O2 and X1 collapsed and X still to play", g.board[0])
+	}
+
+	for _, s := range g.sprites {
+		if isMarkTexture(s.TextureID) && s.Fade > 0 {
+			t.Errorf("a spooky mark sprite is left after the collapse: %+v", s)
+		}
+	}
+}
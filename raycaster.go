@@ -11,6 +11,7 @@ import "math"
 // distance is the distance from the ray origin to the hit point.
 // wallX is the exact position along the wall where the ray hit (between 0 and 1).
 // side indicates whether a vertical (0) or horizontal (1) wall was hit.
+// face is the face of the hit cell the ray entered through.
 type RayHit struct {
 	hit      bool
 	cellX    int
@@ -18,6 +19,25 @@ type RayHit struct {
 	distance float64
 	wallX    float64
 	side     uint8
+	face     WallFace
+}
+
+// WallFace identifies a face of a wall tile, named after the direction it faces.
+// The map y axis points south.
+type WallFace uint8
+
+const (
+	FaceNorth WallFace = iota
+	FaceEast
+	FaceSouth
+	FaceWest
+	FaceCount
+)
+
+// Mirrored returns true if wallX grows from the right to the left of the screen on this face.
+// Textures must be mirrored on these faces so they read the same way on every wall.
+func (f WallFace) Mirrored() bool {
+	return f == FaceEast || f == FaceNorth
 }
 
 // Grid defines the interface for accessing the world map grid.
@@ -151,6 +171,9 @@ func CastRay(
 	hitCellX := mapX
 	hitCellY := mapY
 
+	// the ray enters the cell through the face opposite to its step direction
+	face := hitFace(side, stepX, stepY)
+
 	// compute the hit position along the wall (between 0 and 1)
 	// for a vertical wall we use the y coordinate at the hit point
 	// for a horizontal wall we use the x coordinate at the hit point
@@ -169,7 +192,23 @@ func CastRay(
 		distance: distance,
 		wallX:    wallX,
 		side:     side,
+		face:     face,
+	}
+}
+
+// hitFace returns the face hit by a ray crossing a vertical (side 0) or horizontal (side 1) grid line
+// while stepping in the given directions.
+func hitFace(side uint8, stepX, stepY int) WallFace {
+	if side == 0 {
+		if stepX > 0 {
+			return FaceWest
+		}
+		return FaceEast
+	}
+	if stepY > 0 {
+		return FaceNorth
 	}
+	return FaceSouth
 }
 
 // noHit returns a RayHit indicating that no wall was hit.
@@ -68,6 +68,9 @@ func TestRayCast(t *testing.T) {
 	if hit.side != 1 {
 		t.Errorf("wrong side")
 	}
+	if hit.face != FaceNorth {
+		t.Errorf("wrong face")
+	}
 
 	// check dist
 	// wall at y=2, player at y=0.5 -> dist 1.5
@@ -89,3 +92,53 @@ func TestRayCast(t *testing.T) {
 		t.Errorf("wrong distance calculation")
 	}
 }
+
+func TestRayCast_Faces(t *testing.T) {
+	// 5x5 grid with one wall at (2,2), rays start next to each face
+	tiles := make([][]TileID, 5)
+	for y := range tiles {
+		tiles[y] = make([]TileID, 5)
+	}
+	tiles[2][2] = 1
+	grid := MockGrid{width: 5, height: 5, tiles: tiles}
+
+	tests := []struct {
+		name  string
+		start Vec2
+		dir   Vec2
+		face  WallFace
+	}{
+		{name: "From north", start: Vec2{2.5, 0.5}, dir: Vec2{0, 1}, face: FaceNorth},
+		{name: "From south", start: Vec2{2.5, 4.5}, dir: Vec2{0, -1}, face: FaceSouth},
+		{name: "From west", start: Vec2{0.5, 2.5}, dir: Vec2{1, 0}, face: FaceWest},
+		{name: "From east", start: Vec2{4.5, 2.5}, dir: Vec2{-1, 0}, face: FaceEast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit := CastRay(tt.start, tt.dir, grid, 100)
+			if !hit.hit || hit.face != tt.face {
+				t.Errorf("hit=%v face=%d, want face %d", hit.hit, hit.face, tt.face)
+			}
+		})
+	}
+}
+
+func TestWallTextureU_NotMirrored(t *testing.T) {
+	// a ray slightly right of the centre column hits every face right of its middle
+	tiles := make([][]TileID, 5)
+	for y := range tiles {
+		tiles[y] = make([]TileID, 5)
+	}
+	tiles[2][2] = 1
+	grid := MockGrid{width: 5, height: 5, tiles: tiles}
+
+	for _, dir := range []Vec2{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+		start := Vec2{2.5, 2.5}.Sub(dir.Scale(2))
+		right := GetRayDirection(dir, 1, 0.1)
+		hit := CastRay(start, right, grid, 100)
+		if u := wallTextureU(hit); u <= 0.5 {
+			t.Errorf("dir %v: u = %.2f, want > 0.5", dir, u)
+		}
+	}
+}
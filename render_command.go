@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/png"
+	"math"
+	"os"
+	"runtime"
+)
+
+// RenderCommandName is the first argument that renders a frame to a png instead of starting the game.
+const RenderCommandName = "render"
+
+// RenderMaxSize is the largest width and height of a rendered frame, an 8K image.
+const RenderMaxSize = 8192
+
+// renderOptions are the arguments of the render command.
+// Angle is the view direction in degrees, 0 looks east (+x) and 90 south (+y).
+// MapPath is empty for the built-in map, Sprites adds the built-in decorations.
+// PackPath is an asset pack drawn instead of the embedded textures, empty for none.
+type renderOptions struct {
+	MapPath  string
+	PackPath string
+	Pos      Vec2
+	Angle    float64
+	FOV      float64
+	Width    int
+	Height   int
+	Sprites  bool
+	Out      string
+}
+
+// runRenderCommand renders one frame with the software renderer and writes it as a png.
+// It does not open a window, so it can run in scripts and tests.
+func runRenderCommand(args []string) error {
+	opts, err := parseRenderOptions(args)
+	if err != nil {
+		return err
+	}
+
+	m := NewMap()
+	if opts.MapPath != "" {
+		m, err = LoadMapFile(opts.MapPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	textures, err := LoadTexturePixels()
+	if err != nil {
+		return fmt.Errorf("load textures: %w", err)
+	}
+
+	// unlike the game, a broken pack is an error so scripts notice it
+	types := spriteTypes
+	if opts.PackPath != "" {
+		pack, errP := LoadAssetPack(opts.PackPath)
+		if errP != nil {
+			return fmt.Errorf("load asset pack: %w", errP)
+		}
+		textures, types = pack.Apply(textures, types)
+	}
+
+	r, err := renderFrame(m, textures, types, opts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(opts.Out)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", opts.Out, err)
+	}
+	if errE := png.Encode(f, r.Image()); errE != nil {
+		_ = f.Close()
+		return fmt.Errorf("encode %q: %w", opts.Out, errE)
+	}
+	if errC := f.Close(); errC != nil {
+		return fmt.Errorf("close %q: %w", opts.Out, errC)
+	}
+	return nil
+}
+
+// parseRenderOptions parses and validates the render command arguments.
+func parseRenderOptions(args []string) (renderOptions, error) {
+	opts := renderOptions{
+		MapPath:  "",
+		PackPath: "",
+		Pos:      Vec2{X: DefaultPlayerXSpawnX, Y: DefaultPlayerXSpawnY},
+		Angle:    0,
+		FOV:      PlayerFOV,
+		Width:    WindowSizeX,
+		Height:   WindowSizeY,
+		Sprites:  true,
+		Out:      "",
+	}
+
+	flags := flag.NewFlagSet(RenderCommandName, flag.ContinueOnError)
+	flags.StringVar(&opts.MapPath, "map", opts.MapPath, "json map file, the built-in map when empty")
+	flags.StringVar(&opts.PackPath, "pack", opts.PackPath, "asset pack directory or zip file")
+	flags.Float64Var(&opts.Pos.X, "x", opts.Pos.X, "camera x position")
+	flags.Float64Var(&opts.Pos.Y, "y", opts.Pos.Y, "camera y position")
+	flags.Float64Var(&opts.Angle, "angle", opts.Angle, "view direction in degrees, 0 is east and 90 south")
+	flags.Float64Var(&opts.FOV, "fov", opts.FOV, "field of view in radians")
+	flags.IntVar(&opts.Width, "width", opts.Width, "image width in pixels")
+	flags.IntVar(&opts.Height, "height", opts.Height, "image height in pixels")
+	flags.BoolVar(&opts.Sprites, "sprites", opts.Sprites, "draw the built-in decoration sprites")
+	flags.StringVar(&opts.Out, "out", opts.Out, "output png file")
+
+	if err := flags.Parse(args); err != nil {
+		return renderOptions{}, fmt.Errorf("parse arguments: %w", err)
+	}
+
+	switch {
+	case opts.Out == "":
+		return renderOptions{}, errors.New("missing -out file")
+	case opts.Width <= 1 || opts.Height <= 1 || opts.Width > RenderMaxSize || opts.Height > RenderMaxSize:
+		return renderOptions{}, fmt.Errorf("invalid image size %dx%d, at most %dx%d",
+			opts.Width, opts.Height, RenderMaxSize, RenderMaxSize)
+	case !inRange(opts.FOV, SettingsMinFOV, SettingsMaxFOV):
+		return renderOptions{}, fmt.Errorf("fov %.2f out of range [%.2f, %.2f]", opts.FOV, SettingsMinFOV, SettingsMaxFOV)
+	}
+	return opts, nil
+}
+
+// renderFrame renders the map seen from the options camera into a new software renderer.
+// the map sprites are created from types.
+func renderFrame(m Map, textures TextureMap, types map[string]SpriteType, opts renderOptions) (*SoftwareRenderer, error) {
+	if !m.IsWalkable(opts.Pos) {
+		return nil, fmt.Errorf("camera position %.2f, %.2f is not on a walkable tile", opts.Pos.X, opts.Pos.Y)
+	}
+
+	var sprites []*Sprite
+	if opts.Sprites {
+		sprites = m.NewSprites(types)
+	}
+
+	rad := opts.Angle * math.Pi / 180 //nolint:mnd // degrees to radians
+	r := NewSoftwareRenderer(opts.Width, opts.Height, runtime.GOMAXPROCS(0))
+	r.Render(RenderView{
+		Map:      m,
+		Textures: textures,
+		Pos:      opts.Pos,
+		Dir:      Vec2{X: math.Cos(rad), Y: math.Sin(rad)},
+		FOVScale: GetK(opts.FOV),
+		Sprites:  sprites,
+		Ceiling:  ColorCeiling,
+		Floor:    ColorFloor,
+	})
+	return r, nil
+}
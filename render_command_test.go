@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunRenderCommand_WritesPNG(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "shot.png")
+	args := []string{"--x", "3.5", "--y", "1.5", "--angle", "90", "--width", "160", "--height", "90", "--out", out}
+
+	if err := runRenderCommand(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 160 || b.Dy() != 90 {
+		t.Errorf("image size = %dx%d, want 160x90", b.Dx(), b.Dy())
+	}
+}
+
+func TestRunRenderCommand_MapFile(t *testing.T) {
+	dir := t.TempDir()
+	mapPath := filepath.Join(dir, "m.json")
+	data := []byte(`{"tiles": [[1,1,1],[1,0,1],[1,1,1]], ` + testBoardRooms() + `}`)
+	if err := os.WriteFile(mapPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "shot.png")
+	args := []string{"--map", mapPath, "--x", "1.5", "--y", "1.5", "--sprites=false", "--out", out}
+	if err := runRenderCommand(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunRenderCommand_InvalidArguments(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "shot.png")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Missing out", args: []string{"--x", "3.5", "--y", "1.5"}},
+		{name: "Inside a wall", args: []string{"--x", "0.5", "--y", "0.5", "--out", out}},
+		{name: "Invalid size", args: []string{"--width", "0", "--out", out}},
+		{name: "Too large", args: []string{"--width", "100000", "--height", "100000", "--out", out}},
+		{name: "Invalid fov", args: []string{"--fov", "4", "--out", out}},
+		{name: "NaN fov", args: []string{"--fov", "NaN", "--out", out}},
+		{name: "Missing map", args: []string{"--map", "missing.json", "--out", out}},
+		{name: "Unknown flag", args: []string{"--zoom", "2", "--out", out}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runRenderCommand(tt.args); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Error("no file should be written on errors")
+	}
+}
+
+func TestRunRenderCommand_AssetPack(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		AssetPackManifestName: []byte(`{"walls": [{"tile": 1, "file": "red.png"}]}`),
+		"red.png":             solidPNG(t, 16, 16, color.RGBA{R: 255, A: 255}),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the wall at (7,1) is a brick wall (tile 1), drawn with the low resolution red texture of the pack
+	out := filepath.Join(dir, "shot.png")
+	args := []string{"--pack", dir, "--x", "3.5", "--y", "1.5", "--width", "160", "--height", "90", "--out", out}
+	if err := runRenderCommand(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := readPNG(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, g, b, _ := img.At(80, 45).RGBA()
+	if r == 0 || g != 0 || b != 0 {
+		t.Errorf("centre pixel = %d,%d,%d, want a shaded red", r>>8, g>>8, b>>8)
+	}
+
+	// a broken pack is an error for the render command
+	args = []string{"--pack", filepath.Join(dir, "missing"), "--x", "3.5", "--y", "1.5", "--out", out}
+	if err := runRenderCommand(args); err == nil {
+		t.Error("expected an error for a missing pack")
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+const (
+	SettingsFileName = "settings.json"
+	SettingsDirName  = "gopher-dungeon"
+
+	SettingsMinFOV             = 0.5
+	SettingsMaxFOV             = 2.8
+	SettingsFOVStep            = 0.05
+	SettingsMaxSpeed           = 50.0
+	SettingsMinSpeedMultiplier = 1.0
+	SettingsMaxSpeedMultiplier = 10.0
+	SettingsMaxTurnTimeLimit   = 600.0
+)
+
+// Settings holds the user tunable options of the game.
+// FOV is the horizontal field of view in radians.
+// MovementSpeed is in units per second and RotationSpeed in radians per second.
+// SpeedMultiplier is applied to the movement speed while shift is held.
+// CeilingColor and FloorColor are hex colors like "#19191e".
+// ShowMinimap toggles the minimap overlay and Fullscreen the window mode on desktop.
+// MinimapFog hides the tiles the current player has not seen yet on the minimap,
+// MinimapMarks shows the claimed rooms with their mark even where they were not seen.
+// MinimapRotate turns the minimap around the current player so they always look up.
+// BoardAssist marks the cells of the HUD board where the current player would win or must block.
+// TurnTimeLimit is the time in seconds a player has to place a mark, 0 disables the timer,
+// and TurnTimeout what happens when it runs out.
+// Volume is the master volume, MusicVolume and EffectsVolume are relative to it (all between 0 and 1).
+// Muted silences every sound without losing the volumes.
+// Renderer selects the gpu or the software world renderer.
+// AssetPack is an asset pack directory or zip loaded at startup, empty for the embedded assets only.
+type Settings struct {
+	FOV             float64         `json:"fov"`
+	MovementSpeed   float64         `json:"movementSpeed"`
+	RotationSpeed   float64         `json:"rotationSpeed"`
+	SpeedMultiplier float64         `json:"speedMultiplier"`
+	CeilingColor    string          `json:"ceilingColor"`
+	FloorColor      string          `json:"floorColor"`
+	ShowMinimap     bool            `json:"showMinimap"`
+	MinimapFog      bool            `json:"minimapFog"`
+	MinimapMarks    bool            `json:"minimapMarks"`
+	MinimapRotate   bool            `json:"minimapRotate"`
+	BoardAssist     bool            `json:"boardAssist"`
+	TurnTimeLimit   float64         `json:"turnTimeLimit"`
+	TurnTimeout     TurnTimeoutKind `json:"turnTimeout"`
+	Fullscreen      bool            `json:"fullscreen"`
+	Volume          float64         `json:"volume"`
+	MusicVolume     float64         `json:"musicVolume"`
+	EffectsVolume   float64         `json:"effectsVolume"`
+	Muted           bool            `json:"muted"`
+	Renderer        RendererKind    `json:"renderer"`
+	AssetPack       string          `json:"assetPack,omitempty"`
+}
+
+// DefaultSettings returns the settings matching the built-in constants.
+func DefaultSettings() Settings {
+	return Settings{
+		FOV:             PlayerFOV,
+		MovementSpeed:   PlayerMovementSpeed,
+		RotationSpeed:   PlayerRotationSpeed,
+		SpeedMultiplier: PlayerMovementSpeedMultiplicator,
+		CeilingColor:    formatHexColor(ColorCeiling),
+		FloorColor:      formatHexColor(ColorFloor),
+		ShowMinimap:     true,
+		MinimapFog:      true,
+		MinimapMarks:    true,
+		MinimapRotate:   false,
+		BoardAssist:     false,
+		TurnTimeLimit:   0,
+		TurnTimeout:     TurnTimeoutSkip,
+		Fullscreen:      false,
+		Volume:          AudioDefaultVolume,
+		MusicVolume:     AudioDefaultVolume,
+		EffectsVolume:   AudioDefaultVolume,
+		Muted:           false,
+		Renderer:        RendererGPU,
+		AssetPack:       "",
+	}
+}
+
+// Normalized returns a copy of the settings where every invalid field is replaced by its default value.
+// The returned error lists the fields that were reset, the settings are always usable.
+func (s Settings) Normalized() (Settings, error) {
+	def := DefaultSettings()
+	var errs []error
+
+	if !inRange(s.FOV, SettingsMinFOV, SettingsMaxFOV) {
+		errs = append(errs, fmt.Errorf("fov %.2f out of range [%.2f, %.2f]", s.FOV, SettingsMinFOV, SettingsMaxFOV))
+		s.FOV = def.FOV
+	}
+	if !isValidSpeed(s.MovementSpeed) {
+		errs = append(errs, fmt.Errorf("movement speed %.2f out of range (0, %.0f]", s.MovementSpeed, SettingsMaxSpeed))
+		s.MovementSpeed = def.MovementSpeed
+	}
+	if !isValidSpeed(s.RotationSpeed) {
+		errs = append(errs, fmt.Errorf("rotation speed %.2f out of range (0, %.0f]", s.RotationSpeed, SettingsMaxSpeed))
+		s.RotationSpeed = def.RotationSpeed
+	}
+	if !inRange(s.SpeedMultiplier, SettingsMinSpeedMultiplier, SettingsMaxSpeedMultiplier) {
+		errs = append(errs, fmt.Errorf(
+			"speed multiplier %.2f out of range [%.0f, %.0f]",
+			s.SpeedMultiplier,
+			SettingsMinSpeedMultiplier,
+			SettingsMaxSpeedMultiplier,
+		))
+		s.SpeedMultiplier = def.SpeedMultiplier
+	}
+	if _, err := parseHexColor(s.CeilingColor); err != nil {
+		errs = append(errs, fmt.Errorf("ceiling color: %w", err))
+		s.CeilingColor = def.CeilingColor
+	}
+	if _, err := parseHexColor(s.FloorColor); err != nil {
+		errs = append(errs, fmt.Errorf("floor color: %w", err))
+		s.FloorColor = def.FloorColor
+	}
+	if !isValidVolume(s.Volume) {
+		errs = append(errs, fmt.Errorf("volume %.2f out of range [0, 1]", s.Volume))
+		s.Volume = def.Volume
+	}
+	if !isValidVolume(s.MusicVolume) {
+		errs = append(errs, fmt.Errorf("music volume %.2f out of range [0, 1]", s.MusicVolume))
+		s.MusicVolume = def.MusicVolume
+	}
+	if !isValidVolume(s.EffectsVolume) {
+		errs = append(errs, fmt.Errorf("effects volume %.2f out of range [0, 1]", s.EffectsVolume))
+		s.EffectsVolume = def.EffectsVolume
+	}
+	if s.Renderer != RendererGPU && s.Renderer != RendererSoftware {
+		errs = append(errs, fmt.Errorf("renderer %q is not %q or %q", s.Renderer, RendererGPU, RendererSoftware))
+		s.Renderer = def.Renderer
+	}
+	if !inRange(s.TurnTimeLimit, 0, SettingsMaxTurnTimeLimit) {
+		errs = append(errs, fmt.Errorf("turn time limit %.0f out of range [0, %.0f]",
+			s.TurnTimeLimit, SettingsMaxTurnTimeLimit))
+		s.TurnTimeLimit = def.TurnTimeLimit
+	}
+	if !isValidTurnTimeout(s.TurnTimeout) {
+		errs = append(errs, fmt.Errorf("turn timeout %q is not %q, %q or %q",
+			s.TurnTimeout, TurnTimeoutSkip, TurnTimeoutRandom, TurnTimeoutForfeit))
+		s.TurnTimeout = def.TurnTimeout
+	}
+
+	return s, errors.Join(errs...)
+}
+
+// CeilingRGBA returns the parsed ceiling color, falling back to the default color.
+func (s Settings) CeilingRGBA() color.RGBA {
+	c, err := parseHexColor(s.CeilingColor)
+	if err != nil {
+		return ColorCeiling
+	}
+	return c
+}
+
+// FloorRGBA returns the parsed floor color, falling back to the default color.
+func (s Settings) FloorRGBA() color.RGBA {
+	c, err := parseHexColor(s.FloorColor)
+	if err != nil {
+		return ColorFloor
+	}
+	return c
+}
+
+// MusicGain returns the effective music volume, zero when muted.
+func (s Settings) MusicGain() float64 {
+	if s.Muted {
+		return 0
+	}
+	return s.Volume * s.MusicVolume
+}
+
+// EffectsGain returns the effective sound effects volume, zero when muted.
+func (s Settings) EffectsGain() float64 {
+	if s.Muted {
+		return 0
+	}
+	return s.Volume * s.EffectsVolume
+}
+
+// loadSettings builds the settings from the defaults, the persisted settings and the command line arguments.
+// Later sources override earlier ones. The returned error is informative only, the settings are always valid.
+func loadSettings(args []string) (Settings, error) {
+	s := DefaultSettings()
+	var errs []error
+
+	data, err := readSettingsData()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("read settings: %w", err))
+	}
+	if len(data) > 0 {
+		if errU := json.Unmarshal(data, &s); errU != nil {
+			errs = append(errs, fmt.Errorf("decode settings: %w", errU))
+			s = DefaultSettings()
+		}
+	}
+
+	if errF := applySettingsFlags(args, &s); errF != nil {
+		errs = append(errs, fmt.Errorf("parse flags: %w", errF))
+	}
+
+	s, err = s.Normalized()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid settings: %w", err))
+	}
+
+	return s, errors.Join(errs...)
+}
+
+// saveSettings persists the settings so they are restored on the next start.
+func saveSettings(s Settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	if errW := writeSettingsData(data); errW != nil {
+		return fmt.Errorf("write settings: %w", errW)
+	}
+	return nil
+}
+
+// isValidVolume returns true if v is a volume between AudioMinVolume and AudioMaxVolume.
+func isValidVolume(v float64) bool {
+	return inRange(v, AudioMinVolume, AudioMaxVolume)
+}
+
+// isValidSpeed reports whether v is a usable movement or rotation speed.
+func isValidSpeed(v float64) bool {
+	return v > 0 && v <= SettingsMaxSpeed
+}
+
+// inRange reports whether v lies in [lo, hi]. NaN is never in range.
+func inRange(v, lo, hi float64) bool {
+	return v >= lo && v <= hi
+}
+
+// parseHexColor parses "#rrggbb" or "#rrggbbaa" into a color.
+func parseHexColor(s string) (color.RGBA, error) {
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q: %w", s, err)
+	}
+
+	//nolint:mnd // 6 digits means no alpha channel
+	if len(hex) == 6 {
+		v = v<<8 | 0xff
+	}
+
+	//nolint:mnd // unpack the rrggbbaa channels
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// formatHexColor formats a color as "#rrggbb", or "#rrggbbaa" when it is not opaque.
+func formatHexColor(c color.RGBA) string {
+	//nolint:mnd // opaque alpha
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
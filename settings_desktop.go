@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// settingsFilePath returns the location of the settings file in the user config directory.
+func settingsFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("user config dir: %w", err)
+	}
+	return filepath.Join(dir, SettingsDirName, SettingsFileName), nil
+}
+
+// readSettingsData reads the settings file, a missing file is not an error.
+func readSettingsData() ([]byte, error) {
+	path, err := settingsFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", path, err)
+	}
+	return data, nil
+}
+
+// writeSettingsData writes the settings file, creating its directory if needed.
+func writeSettingsData(data []byte) error {
+	path, err := settingsFilePath()
+	if err != nil {
+		return err
+	}
+
+	//nolint:mnd // rwx for the user, rx for the others
+	if errM := os.MkdirAll(filepath.Dir(path), 0o755); errM != nil {
+		return fmt.Errorf("create %q: %w", filepath.Dir(path), errM)
+	}
+
+	//nolint:mnd // rw for the user, r for the others
+	if errW := os.WriteFile(path, data, 0o644); errW != nil {
+		return fmt.Errorf("write %q: %w", path, errW)
+	}
+	return nil
+}
+
+// applySettingsFlags overrides the settings with the command line flags that were explicitly set.
+func applySettingsFlags(args []string, s *Settings) error {
+	flags := flag.NewFlagSet(WindowTitle, flag.ContinueOnError)
+	flags.Float64Var(&s.FOV, "fov", s.FOV, "field of view in radians")
+	flags.Float64Var(&s.MovementSpeed, "move-speed", s.MovementSpeed, "movement speed in units per second")
+	flags.Float64Var(&s.RotationSpeed, "rotation-speed", s.RotationSpeed, "rotation speed in radians per second")
+	flags.Float64Var(&s.SpeedMultiplier, "speed-multiplier", s.SpeedMultiplier, "movement speed multiplier with shift")
+	flags.StringVar(&s.CeilingColor, "ceiling-color", s.CeilingColor, "ceiling color as #rrggbb")
+	flags.StringVar(&s.FloorColor, "floor-color", s.FloorColor, "floor color as #rrggbb")
+	flags.BoolVar(&s.ShowMinimap, "minimap", s.ShowMinimap, "show the minimap")
+	flags.BoolVar(&s.MinimapFog, "minimap-fog", s.MinimapFog, "hide the unexplored tiles on the minimap")
+	flags.BoolVar(&s.MinimapMarks, "minimap-marks", s.MinimapMarks, "show the claimed rooms on the minimap")
+	flags.BoolVar(&s.MinimapRotate, "minimap-rotate", s.MinimapRotate, "turn the minimap with the player")
+	flags.BoolVar(&s.BoardAssist, "board-assist", s.BoardAssist, "mark the winning and blocking cells on the hud board")
+	flags.BoolVar(&s.Fullscreen, "fullscreen", s.Fullscreen, "start in fullscreen")
+	flags.Float64Var(&s.Volume, "volume", s.Volume, "master volume between 0 and 1")
+	flags.Float64Var(&s.MusicVolume, "music-volume", s.MusicVolume, "music volume between 0 and 1")
+	flags.Float64Var(&s.EffectsVolume, "effects-volume", s.EffectsVolume, "sound effects volume between 0 and 1")
+	flags.BoolVar(&s.Muted, "mute", s.Muted, "start muted")
+	flags.StringVar(&s.AssetPack, "pack", s.AssetPack, "asset pack directory or zip file")
+	flags.Func("renderer", "world renderer, gpu or software", func(v string) error {
+		s.Renderer = RendererKind(v)
+		return nil
+	})
+	flags.Float64Var(&s.TurnTimeLimit, "turn-time", s.TurnTimeLimit, "seconds per turn, 0 for no limit")
+	flags.Func("turn-timeout", "when the turn time runs out: skip, random or forfeit", func(v string) error {
+		s.TurnTimeout = TurnTimeoutKind(v)
+		return nil
+	})
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("parse arguments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !js
+
+package main
+
+import "testing"
+
+func TestApplySettingsFlags_OverridesOnlySetFlags(t *testing.T) {
+	s := DefaultSettings()
+	s.MovementSpeed = 7
+
+	if err := applySettingsFlags([]string{"-fov", "1.1", "-minimap=false", "-minimap-rotate", "-board-assist", "-turn-time", "30", "-turn-timeout", "forfeit"}, &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.FOV != 1.1 {
+		t.Errorf("FOV = %v, want 1.1", s.FOV)
+	}
+	if s.ShowMinimap {
+		t.Error("ShowMinimap should be false")
+	}
+	if !s.MinimapRotate {
+		t.Error("MinimapRotate should be true")
+	}
+	if !s.BoardAssist {
+		t.Error("BoardAssist should be true")
+	}
+	if s.TurnTimeLimit != 30 || s.TurnTimeout != TurnTimeoutForfeit {
+		t.Errorf("turn timer = %v %q, want 30 forfeit", s.TurnTimeLimit, s.TurnTimeout)
+	}
+	if s.MovementSpeed != 7 {
+		t.Errorf("MovementSpeed = %v, want the value from the file (7)", s.MovementSpeed)
+	}
+}
+
+func TestApplySettingsFlags_UnknownFlag(t *testing.T) {
+	s := DefaultSettings()
+	if err := applySettingsFlags([]string{"-nope"}, &s); err == nil {
+		t.Error("expected an error for an unknown flag")
+	}
+}
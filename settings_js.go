@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build js
+
+package main
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+const SettingsStorageKey = SettingsDirName + "/" + SettingsFileName
+
+// localStorage returns the browser local storage, or an undefined value when it is not available.
+func localStorage() js.Value {
+	return js.Global().Get("localStorage")
+}
+
+// readSettingsData reads the settings from the browser local storage, missing settings are not an error.
+func readSettingsData() ([]byte, error) {
+	storage := localStorage()
+	if storage.IsUndefined() || storage.IsNull() {
+		return nil, errors.New("local storage is not available")
+	}
+
+	item := storage.Call("getItem", SettingsStorageKey)
+	if item.IsNull() || item.IsUndefined() {
+		return nil, nil
+	}
+	return []byte(item.String()), nil
+}
+
+// writeSettingsData stores the settings in the browser local storage.
+func writeSettingsData(data []byte) error {
+	storage := localStorage()
+	if storage.IsUndefined() || storage.IsNull() {
+		return errors.New("local storage is not available")
+	}
+
+	storage.Call("setItem", SettingsStorageKey, string(data))
+	return nil
+}
+
+// applySettingsFlags is a no-op in the browser, there is no command line.
+func applySettingsFlags(_ []string, _ *Settings) error {
+	return nil
+}
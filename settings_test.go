@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestSettings_NormalizedKeepsValid(t *testing.T) {
+	s := DefaultSettings()
+	s.FOV = 1.2
+	s.CeilingColor = "#102030"
+	s.Renderer = RendererSoftware
+
+	got, err := s.Normalized()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != s {
+		t.Errorf("Normalized() = %+v, want %+v", got, s)
+	}
+}
+
+func TestSettings_NormalizedResetsInvalid(t *testing.T) {
+	def := DefaultSettings()
+
+	s := def
+	s.FOV = 4
+	s.MovementSpeed = -1
+	s.SpeedMultiplier = 0
+	s.FloorColor = "red"
+	s.Renderer = "vulkan"
+	s.TurnTimeLimit = -3
+	s.TurnTimeout = "sulk"
+
+	got, err := s.Normalized()
+	if err == nil {
+		t.Fatal("expected an error for invalid settings")
+	}
+	if got != def {
+		t.Errorf("Normalized() = %+v, want defaults %+v", got, def)
+	}
+}
+
+func TestSettings_NormalizedResetsNaN(t *testing.T) {
+	def := DefaultSettings()
+
+	s := def
+	s.FOV = math.NaN()
+	s.MovementSpeed = math.NaN()
+	s.RotationSpeed = math.NaN()
+	s.SpeedMultiplier = math.NaN()
+	s.Volume = math.NaN()
+	s.TurnTimeLimit = math.NaN()
+
+	got, err := s.Normalized()
+	if err == nil {
+		t.Fatal("expected an error for NaN settings")
+	}
+	if got != def {
+		t.Errorf("Normalized() = %+v, want defaults %+v", got, def)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{in: "#ff8000", want: color.RGBA{255, 128, 0, 255}},
+		{in: "#ff800080", want: color.RGBA{255, 128, 0, 128}},
+		{in: "ff8000", wantErr: true},
+		{in: "#ff80", wantErr: true},
+		{in: "#gg8000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseHexColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHexColor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseHexColor(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatHexColor_RoundTrip(t *testing.T) {
+	for _, c := range []color.RGBA{ColorCeiling, ColorFloor, {1, 2, 3, 4}} {
+		got, err := parseHexColor(formatHexColor(c))
+		if err != nil || got != c {
+			t.Errorf("round trip of %v = %v, %v", c, got, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RendererKind selects how the world view is drawn.
+type RendererKind string
+
+const (
+	// RendererGPU draws every wall and sprite column with its own DrawImage call.
+	RendererGPU RendererKind = "gpu"
+	// RendererSoftware computes the frame on the CPU and uploads it once with WritePixels.
+	RendererSoftware RendererKind = "software"
+)
+
+// rgbaBytesPerPixel is the size of one pixel in the RGBA pixel buffer.
+const rgbaBytesPerPixel = 4
+
+// RenderView is everything the software renderer needs to draw one frame.
+// Pos and Dir are the camera position and direction, FOVScale the camera plane length.
+// Sprites are drawn back to front, hidden ones are skipped.
+// ClipBottom is the first row sprites are not drawn on (e.g. the HUD top), 0 draws them on the whole frame.
+type RenderView struct {
+	Map        Map
+	Textures   TextureMap
+	Pos        Vec2
+	Dir        Vec2
+	FOVScale   float64
+	Sprites    []*Sprite
+	Ceiling    color.RGBA
+	Floor      color.RGBA
+	ClipBottom int
+}
+
+// SoftwareRenderer draws the world on the CPU into an RGBA pixel buffer.
+// The screen is split into column bands rendered in parallel, one goroutine per band.
+// Each band owns its columns (pixels, zBuffer, wallRows and hits), so no locking is needed while drawing.
+// frame is the ebiten image the pixels are uploaded to, created on the first Draw.
+type SoftwareRenderer struct {
+	width   int
+	height  int
+	workers int
+
+	pixels   []byte
+	zBuffer  []float64
+	wallRows []rowRange
+	hits     []RayHit
+	sprites  []spriteProjection
+
+	frame *ebiten.Image
+}
+
+// NewSoftwareRenderer creates a renderer for a width x height frame using up to workers goroutines.
+func NewSoftwareRenderer(width, height, workers int) *SoftwareRenderer {
+	return &SoftwareRenderer{
+		width:    width,
+		height:   height,
+		workers:  max(workers, 1),
+		pixels:   make([]byte, width*height*rgbaBytesPerPixel),
+		zBuffer:  make([]float64, width),
+		wallRows: make([]rowRange, width),
+		hits:     make([]RayHit, width),
+		sprites:  nil,
+		frame:    nil,
+	}
+}
+
+// Render computes the frame into the pixel buffer.
+// sprites are projected once, then every band draws its walls followed by the sprites over them.
+func (r *SoftwareRenderer) Render(v RenderView) {
+	r.projectSprites(v)
+
+	bands := min(r.workers, r.width)
+	if bands <= 1 {
+		r.renderBand(v, 0, r.width)
+		return
+	}
+
+	bandWidth := (r.width + bands - 1) / bands
+
+	var wg sync.WaitGroup
+	for start := 0; start < r.width; start += bandWidth {
+		end := min(start+bandWidth, r.width)
+		wg.Go(func() {
+			r.renderBand(v, start, end)
+		})
+	}
+	wg.Wait()
+}
+
+// Draw uploads the pixel buffer and draws it on the screen.
+func (r *SoftwareRenderer) Draw(screen *ebiten.Image) {
+	if screen == nil {
+		return
+	}
+
+	if r.frame == nil {
+		r.frame = ebiten.NewImage(r.width, r.height)
+	}
+
+	r.frame.WritePixels(r.pixels)
+	screen.DrawImage(r.frame, nil)
+}
+
+// Image returns the last rendered frame. The image shares the renderer buffer.
+func (r *SoftwareRenderer) Image() *image.RGBA {
+	return &image.RGBA{
+		Pix:    r.pixels,
+		Stride: r.width * rgbaBytesPerPixel,
+		Rect:   image.Rect(0, 0, r.width, r.height),
+	}
+}
+
+// Hit returns the ray hit of screen column x during the last frame.
+func (r *SoftwareRenderer) Hit(x int) RayHit {
+	return r.hits[x]
+}
+
+// VisibleSprites returns the number of sprites in front of the camera and on screen during the last frame.
+func (r *SoftwareRenderer) VisibleSprites() int {
+	return len(r.sprites)
+}
+
+// projectSprites projects the visible sprites, sorted back to front.
+func (r *SoftwareRenderer) projectSprites(v RenderView) {
+	r.sprites = r.sprites[:0]
+
+	plane := cameraPlane(v.Dir, v.FOVScale)
+	for _, s := range SortSpritesByDistance(v.Sprites, v.Pos) {
+		if sp, ok := projectSprite(v.Pos, v.Dir, plane, s, r.width, r.height); ok {
+			texture := v.Textures[s.TextureID]
+			sp.frame = directionalFrame(s.Facing, v.Pos.Sub(s.Position), texture.Frames)
+			sp.texSize = texture.frameSize()
+			r.sprites = append(r.sprites, sp)
+
+			// the subscript comes after the sprite so it is drawn over it
+			if sub, okS := subscriptProjection(sp, s.Subscript, r.width); okS {
+				sub.texSize = v.Textures[Digits].frameSize()
+				r.sprites = append(r.sprites, sub)
+			}
+		}
+	}
+}
+
+// renderBand draws the columns in [start, end): ceiling, floor and walls, then the sprites.
+func (r *SoftwareRenderer) renderBand(v RenderView, start, end int) {
+	for x := start; x < end; x++ {
+		r.renderWallColumn(v, x)
+	}
+
+	for _, sp := range r.sprites {
+		r.renderSpriteColumns(v, sp, start, end)
+	}
+}
+
+// renderWallColumn casts the ray of column x, fills the ceiling and floor and draws the wall slice.
+func (r *SoftwareRenderer) renderWallColumn(v RenderView, x int) {
+	half := r.height / Two
+	for y := range r.height {
+		if y < half {
+			r.setPixel(x, y, v.Ceiling)
+		} else {
+			r.setPixel(x, y, v.Floor)
+		}
+	}
+
+	rayDir := GetRayDirection(v.Dir, v.FOVScale, GetCameraX(x, r.width))
+	hit := CastRay(v.Pos, rayDir, v.Map, v.Map.MaxRayIterations())
+	r.hits[x] = hit
+	r.zBuffer[x] = math.Inf(1)
+	r.wallRows[x] = rowRange{start: 0, end: 0}
+
+	if !hit.hit || math.IsInf(hit.distance, 1) || hit.distance <= 0 {
+		return
+	}
+	r.zBuffer[x] = hit.distance
+
+	textureID, ok := v.Map.WallTexture(hit.cellX, hit.cellY, hit.face)
+	if !ok {
+		return
+	}
+	texture := v.Textures[textureID]
+	if texture.Pixels == nil {
+		return
+	}
+
+	lineH := float64(r.height) / hit.distance
+	top := float64(half) - lineH/Two
+	texelH := lineH / float64(texture.frameSize())
+	texX := texture.frameColumn(0, wallTextureU(hit))
+
+	rows := pixelRows(top, top+lineH, r.height)
+	r.wallRows[x] = rows
+	r.drawTextureRows(texture, texX, 0, x, top, texelH, rows, wallShade(hit), 1)
+}
+
+// renderSpriteColumns draws the columns of a projected sprite that are in [start, end) and in front of the walls.
+func (r *SoftwareRenderer) renderSpriteColumns(v RenderView, sp spriteProjection, start, end int) {
+	texture := v.Textures[sp.textureID]
+	if texture.Pixels == nil {
+		return
+	}
+
+	shade := spriteShade(sp)
+	opacity := spriteOpacity(sp)
+
+	clipBottom := r.height
+	if v.ClipBottom > 0 {
+		clipBottom = min(v.ClipBottom, r.height)
+	}
+
+	for x := max(sp.startX, start); x <= min(sp.endX, end-1); x++ {
+		u := sp.textureU(x)
+		if u < 0 || u > 1 {
+			continue
+		}
+
+		// only the visible rows: on screen, above the clip row, not behind the wall and not transparent
+		texX := texture.frameColumn(sp.frame, u)
+		opaque := texture.opaqueRows(sp.row, texX)
+		for _, rows := range spriteColumnRows(sp, opaque, r.zBuffer[x], r.wallRows[x], clipBottom) {
+			r.drawTextureRows(texture, texX, sp.row, x, float64(sp.startY), sp.texelHeight(), rows, shade, opacity)
+		}
+	}
+}
+
+// drawTextureRows draws the texture column texX of the animation row at screen column x, on the given screen rows.
+// the frame starts at row top and texelH is the height of one texel in pixels,
+// like the strips the gpu renderer scales by size/frame size.
+// the texture is shaded, faded by opacity and alpha blended over the buffer (both are premultiplied).
+func (r *SoftwareRenderer) drawTextureRows(
+	texture Texture,
+	texX, row, x int,
+	top, texelH float64,
+	rows rowRange,
+	shade, opacity float32,
+) {
+	tex := texture.Pixels
+	texTop := texture.frameTop(row)
+	size := texture.frameSize()
+
+	for y := rows.start; y < rows.end; y++ {
+		// sample at the pixel center, like nearest filtering does
+		texY := texTop + clampInt(int((float64(y)+0.5-top)/texelH), 0, size-1)
+		src := tex.Pix[texY*tex.Stride+texX*rgbaBytesPerPixel:]
+		if src[3] == 0 {
+			continue
+		}
+		if opacity < 1 {
+			faded := [rgbaBytesPerPixel]byte{}
+			for c := range faded {
+				faded[c] = uint8(float32(src[c])*opacity + 0.5)
+			}
+			src = faded[:]
+		}
+		r.blendPixel(x, y, src, shade)
+	}
+}
+
+// setPixel writes an opaque color in the buffer.
+func (r *SoftwareRenderer) setPixel(x, y int, c color.RGBA) {
+	i := (y*r.width + x) * rgbaBytesPerPixel
+	r.pixels[i] = c.R
+	r.pixels[i+1] = c.G
+	r.pixels[i+2] = c.B
+	r.pixels[i+3] = c.A
+}
+
+// blendPixel draws a premultiplied source pixel over the buffer with the "source over" operator.
+// shade scales the color channels, not the alpha, colors brightened past white are clamped.
+func (r *SoftwareRenderer) blendPixel(x, y int, src []byte, shade float32) {
+	i := (y*r.width + x) * rgbaBytesPerPixel
+	dst := r.pixels[i : i+rgbaBytesPerPixel]
+
+	// opaque texels (every wall texel) replace the pixel
+	if src[3] == math.MaxUint8 {
+		for c := range 3 {
+			dst[c] = uint8(min(float32(src[c])*shade+0.5, math.MaxUint8))
+		}
+		dst[3] = math.MaxUint8
+		return
+	}
+
+	inv := float32(math.MaxUint8-src[3]) / math.MaxUint8
+	for c := range 3 {
+		dst[c] = uint8(min(float32(src[c])*shade+float32(dst[c])*inv+0.5, math.MaxUint8))
+	}
+	dst[3] = uint8(min(float32(src[3])+float32(dst[3])*inv+0.5, math.MaxUint8))
+}
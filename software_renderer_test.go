@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"runtime"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// testRenderView returns a view of the default map from the first room looking east.
+func testRenderView(tb testing.TB) RenderView {
+	tb.Helper()
+
+	textures, err := LoadTextures()
+	if err != nil {
+		tb.Fatalf("load textures: %v", err)
+	}
+
+	return RenderView{
+		Map:      NewMap(),
+		Textures: textures,
+		Pos:      Vec2{X: 1.5, Y: 3.5},
+		Dir:      Vec2{X: 1, Y: 0},
+		FOVScale: GetK(PlayerFOV),
+		Sprites:  NewMap().NewSprites(spriteTypes),
+		Ceiling:  ColorCeiling,
+		Floor:    ColorFloor,
+	}
+}
+
+func pixelAt(r *SoftwareRenderer, x, y int) color.RGBA {
+	c, _ := r.Image().At(x, y).(color.RGBA)
+	return c
+}
+
+func TestSoftwareRenderer_CeilingFloorAndWalls(t *testing.T) {
+	v := testRenderView(t)
+	v.Pos = Vec2{X: 3.5, Y: 1.5}
+
+	r := NewSoftwareRenderer(WindowSizeX, WindowSizeY, 1)
+	r.Render(v)
+
+	// the wall at (7,1) is 3 tiles away, it leaves the top and bottom rows visible
+	if got := pixelAt(r, WindowSizeXDiv2, 0); got != ColorCeiling {
+		t.Errorf("top pixel = %v, want the ceiling color", got)
+	}
+	if got := pixelAt(r, WindowSizeXDiv2, WindowSizeY-1); got != ColorFloor {
+		t.Errorf("bottom pixel = %v, want the floor color", got)
+	}
+
+	hit := r.Hit(WindowSizeXDiv2)
+	if !hit.hit || hit.cellX != 7 || hit.cellY != 1 {
+		t.Fatalf("centre hit = %+v, want the wall at (7,1)", hit)
+	}
+	if got := pixelAt(r, WindowSizeXDiv2, WindowSizeYDiv2); got == ColorCeiling || got == ColorFloor {
+		t.Errorf("centre pixel = %v, want a wall texel", got)
+	}
+}
+
+func TestSoftwareRenderer_SpriteOcclusion(t *testing.T) {
+	v := testRenderView(t)
+	v.Pos = Vec2{X: 3.5, Y: 1.5}
+	v.Sprites = nil
+
+	r := NewSoftwareRenderer(WindowSizeX, WindowSizeY, 1)
+	r.Render(v)
+	walls := bytes.Clone(r.pixels)
+
+	// a sprite behind the wall is hidden
+	v.Sprites = []*Sprite{spriteTypes["light"].NewSprite(Vec2{X: 9.5, Y: 1.5})}
+	r.Render(v)
+	if !bytes.Equal(walls, r.pixels) {
+		t.Error("sprite behind a wall changed the frame")
+	}
+
+	// the same sprite in front of the wall is drawn
+	v.Sprites[0].Position = Vec2{X: 5.5, Y: 1.5}
+	r.Render(v)
+	if bytes.Equal(walls, r.pixels) {
+		t.Error("sprite in front of the wall is not drawn")
+	}
+	if r.VisibleSprites() != 1 {
+		t.Errorf("visible sprites = %d, want 1", r.VisibleSprites())
+	}
+}
+
+func TestSoftwareRenderer_ClipBottom(t *testing.T) {
+	v := testRenderView(t)
+	v.Sprites = nil
+
+	r := NewSoftwareRenderer(WindowSizeX, WindowSizeY, 1)
+	r.Render(v)
+	walls := bytes.Clone(r.pixels)
+
+	// a lantern right in front of the camera covers the whole height, but not below the clip row
+	v.Sprites = []*Sprite{spriteTypes["light"].NewSprite(v.Pos.Add(Vec2{X: 0.6, Y: 0}))}
+	v.ClipBottom = WindowSizeYDiv2
+	r.Render(v)
+
+	half := WindowSizeYDiv2 * WindowSizeX * rgbaBytesPerPixel
+	if bytes.Equal(walls[:half], r.pixels[:half]) {
+		t.Error("sprite is not drawn above the clip row")
+	}
+	if !bytes.Equal(walls[half:], r.pixels[half:]) {
+		t.Error("sprite is drawn below the clip row")
+	}
+}
+
+func TestSoftwareRenderer_ParallelMatchesSerial(t *testing.T) {
+	v := testRenderView(t)
+
+	serial := NewSoftwareRenderer(WindowSizeX, WindowSizeY, 1)
+	serial.Render(v)
+
+	//nolint:mnd // uneven band widths
+	for _, workers := range []int{2, 7, runtime.GOMAXPROCS(0)} {
+		parallel := NewSoftwareRenderer(WindowSizeX, WindowSizeY, workers)
+		parallel.Render(v)
+		if !bytes.Equal(serial.pixels, parallel.pixels) {
+			t.Errorf("%d workers: frame differs from the serial frame", workers)
+		}
+	}
+}
+
+// TestSoftwareRenderer_HighResolutionTextures renders the same view with every texture scaled up twice.
+// nearest sampling of a twice larger texture picks the same texels, so the frames must be identical.
+func TestSoftwareRenderer_HighResolutionTextures(t *testing.T) {
+	v := testRenderView(t)
+	v.Pos = Vec2{X: 10.5, Y: 4.5}
+	v.Dir = Vec2{X: -0.3, Y: -1}.Normalize()
+
+	r := NewSoftwareRenderer(WindowSizeX, WindowSizeY, 1)
+	r.Render(v)
+	want := bytes.Clone(r.pixels)
+
+	hiRes := make(TextureMap, len(v.Textures))
+	for id, texture := range v.Textures {
+		tex, err := newTexture(scaleUpPixels(texture.Pixels, 2), texture.Frames)
+		if err != nil {
+			t.Fatalf("texture %d: %v", id, err)
+		}
+		hiRes[id] = tex
+	}
+	v.Textures = hiRes
+
+	r.Render(v)
+	if !bytes.Equal(want, r.pixels) {
+		t.Error("the frame differs with twice larger textures")
+	}
+}
+
+// scaleUpPixels returns the image scaled by an integer factor with nearest filtering.
+func scaleUpPixels(src *image.RGBA, factor int) *image.RGBA {
+	b := src.Rect
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()*factor, b.Dy()*factor))
+	for y := range out.Rect.Dy() {
+		for x := range out.Rect.Dx() {
+			out.SetRGBA(x, y, src.RGBAAt(b.Min.X+x/factor, b.Min.Y+y/factor))
+		}
+	}
+	return out
+}
+
+func TestBlendPixel(t *testing.T) {
+	r := NewSoftwareRenderer(1, 1, 1)
+	r.setPixel(0, 0, color.RGBA{R: 200, G: 100, B: 0, A: 255})
+
+	// half transparent premultiplied white, shaded by half
+	r.blendPixel(0, 0, []byte{128, 128, 128, 128}, 0.5)
+
+	want := color.RGBA{R: 164, G: 114, B: 64, A: 255}
+	if got := pixelAt(r, 0, 0); got != want {
+		t.Errorf("blended pixel = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkSoftwareRenderer(b *testing.B) {
+	v := testRenderView(b)
+
+	for _, workers := range []int{1, runtime.GOMAXPROCS(0)} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			r := NewSoftwareRenderer(WindowSizeX, WindowSizeY, workers)
+			for b.Loop() {
+				r.Render(v)
+			}
+		})
+	}
+}
+
+// BenchmarkWorldDraw compares both renderers through World.Draw, the gpu path issues a DrawImage per column.
+// outside of the game loop the draw calls are only queued, so it measures their cpu cost.
+func BenchmarkWorldDraw(b *testing.B) {
+	v := testRenderView(b)
+
+	p := NewPlayer(v.Pos.X, v.Pos.Y, PlayerSymbolX, "X")
+	p.dir = v.Dir
+	g := &Game{
+		playerX:       p,
+		playerO:       NewPlayer(v.Pos.X+2, v.Pos.Y, PlayerSymbolO, "O"),
+		currentPlayer: p,
+		sprites:       v.Sprites,
+		assets:        &Assets{NormalTextFace: nil, BigTextFace: nil, Textures: v.Textures},
+		worldMap:      v.Map,
+		debug:         &DebugOverlay{},
+	}
+
+	for _, kind := range []RendererKind{RendererGPU, RendererSoftware} {
+		b.Run(string(kind), func(b *testing.B) {
+			w := &World{fovScale: v.FOVScale, ceilingColor: v.Ceiling, floorColor: v.Floor, renderer: kind}
+			screen := ebiten.NewImage(WindowSizeX, WindowSizeY)
+			for b.Loop() {
+				w.Draw(screen, g)
+			}
+		})
+	}
+}
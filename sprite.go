@@ -3,7 +3,10 @@
 
 package main
 
-import "sort"
+import (
+	"math"
+	"sort"
+)
 
 // Sprite represents a 2D image in the game world.
 // Position is the world coordinates of the sprite's center.
@@ -11,12 +14,24 @@ import "sort"
 // Scale is a multiplier for the sprite's size.
 // Z is the vertical offset of the sprite in the world.
 // Hidden indicates whether the sprite should be rendered or not.
+// Facing is the direction the sprite looks at, it picks the frame of directional sprite sheets.
+// Anim is the animation playing, it picks the frame row of animated sprite sheets.
+// Glow brightens the sprite, 0 draws it with the distance shade only and 1 twice as bright.
+// Level is the level of the dungeon the sprite stands on, 0 for the ground, it is only seen from there.
+// Fade makes the sprite see-through, 0 draws it opaque and 1 invisible.
+// Subscript is the number from 1 to 9 drawn at the bottom right of the sprite, 0 draws none.
 type Sprite struct {
 	Position  Vec2
 	TextureID TextureID
 	Scale     float64
 	Z         float64
 	Hidden    bool
+	Facing    Vec2
+	Anim      AnimationState
+	Glow      float64
+	Level     int
+	Fade      float64
+	Subscript int
 }
 
 const SkeletonSkullScale = 0.5
@@ -24,100 +39,55 @@ const SkeletonSkullZ = -1.0
 const ChainsScale = 0.5
 const ChainsZ = 0.5
 
-func createSprites() []*Sprite {
-	return []*Sprite{
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 2.2, Y: 4.7},
-			TextureID: Light,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 11.5, Y: 1.4},
-			TextureID: Light,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 8.4, Y: 14.6},
-			TextureID: Light,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 18.2, Y: 18.6},
-			TextureID: Light,
-			Hidden:    false,
-		},
+// SpriteType describes a kind of decoration that can be spawned by name.
+// Animation is played by the spawned sprites, nil for a still sprite.
+type SpriteType struct {
+	TextureID TextureID
+	Scale     float64
+	Z         float64
+	Animation *Animation
+}
+
+//nolint:gochecknoglobals // decoration sprite types by name
+var spriteTypes = map[string]SpriteType{
+	"light":  {TextureID: Light, Scale: 1.0, Z: 0.0, Animation: &AnimLanternFlicker},
+	"skull":  {TextureID: SkeletonSkull, Scale: SkeletonSkullScale, Z: SkeletonSkullZ, Animation: nil},
+	"chains": {TextureID: Chains, Scale: ChainsScale, Z: ChainsZ, Animation: nil},
+	"poof":   {TextureID: Poof, Scale: 1.0, Z: 0.0, Animation: &AnimPoof},
+}
 
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 2.9, Y: 5.3},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 10.6, Y: 5.1},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 15.9, Y: 4.4},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 5.2, Y: 11.7},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 12.7, Y: 12.4},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 17.1, Y: 17.3},
-			TextureID: SkeletonSkull,
-			Scale:     SkeletonSkullScale,
-			Z:         SkeletonSkullZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 6.4, Y: 3.2},
-			TextureID: Chains,
-			Scale:     ChainsScale,
-			Z:         ChainsZ,
-			Hidden:    false,
-		},
-		{
-			//nolint:mnd // position on the map
-			Position:  Vec2{X: 14.8, Y: 10.6},
-			TextureID: Chains,
-			Scale:     ChainsScale,
-			Z:         ChainsZ,
-			Hidden:    false,
-		},
+// NewSprite creates a visible sprite of the given type at the given position.
+func (t SpriteType) NewSprite(pos Vec2) *Sprite {
+	return &Sprite{
+		Position:  pos,
+		TextureID: t.TextureID,
+		Scale:     t.Scale,
+		Z:         t.Z,
+		Hidden:    false,
+		Facing:    Vec2{X: 0, Y: 0},
+		Anim:      NewAnimationState(t.Animation, 0),
+		Glow:      0,
+		Level:     0,
+		Fade:      0,
+		Subscript: 0,
 	}
 }
 
+// directionalFrame returns the sprite sheet frame showing a sprite facing facing, seen from toViewer
+// (the vector from the sprite to the viewer).
+// frames go clockwise around the sprite: with 8 frames, 0 is the front, 2 its right side, 4 its back
+// and 6 its left side. Sprites without a facing, or sheets of a single frame, always use frame 0.
+func directionalFrame(facing, toViewer Vec2, frames int) int {
+	if frames <= 1 || facing.Len2() == 0 || toViewer.Len2() == 0 {
+		return 0
+	}
+
+	step := 2 * math.Pi / float64(frames)
+	rel := math.Atan2(toViewer.Y, toViewer.X) - math.Atan2(facing.Y, facing.X)
+	frame := int(math.Round(rel / step))
+	return ((frame % frames) + frames) % frames
+}
+
 // SortSpritesByDistance returns a slice of sprites sorted by distance from the reference position (descending).
 // It filters out nil or hidden sprites.
 func SortSpritesByDistance(sprites []*Sprite, refPos Vec2) []*Sprite {
@@ -139,7 +109,8 @@ func SortSpritesByDistance(sprites []*Sprite, refPos Vec2) []*Sprite {
 		})
 	}
 
-	sort.Slice(withDist, func(i, j int) bool {
+	// stable, so sprites at the same place are drawn in slice order (a poof over its mark)
+	sort.SliceStable(withDist, func(i, j int) bool {
 		return withDist[i].d > withDist[j].d
 	})
 
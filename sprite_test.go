@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestDirectionalFrame(t *testing.T) {
+	// the sprite faces east, the viewer walks around it clockwise (y points south)
+	facing := Vec2{X: 1, Y: 0}
+
+	tests := []struct {
+		name     string
+		toViewer Vec2
+		want     int
+	}{
+		{name: "Front", toViewer: Vec2{X: 3, Y: 0}, want: 0},
+		{name: "Front right", toViewer: Vec2{X: 1, Y: 1}, want: 1},
+		{name: "Right side", toViewer: Vec2{X: 0, Y: 2}, want: 2},
+		{name: "Back", toViewer: Vec2{X: -1, Y: 0}, want: 4},
+		{name: "Left side", toViewer: Vec2{X: 0, Y: -1}, want: 6},
+		{name: "Almost front", toViewer: Vec2{X: 1, Y: -0.3}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := directionalFrame(facing, tt.toViewer, 8); got != tt.want {
+				t.Errorf("directionalFrame = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectionalFrame_SingleFrame(t *testing.T) {
+	if got := directionalFrame(Vec2{X: 1, Y: 0}, Vec2{X: -1, Y: 0}, 1); got != 0 {
+		t.Errorf("single frame sheet: frame %d, want 0", got)
+	}
+	if got := directionalFrame(Vec2{X: 0, Y: 0}, Vec2{X: -1, Y: 0}, 8); got != 0 {
+		t.Errorf("sprite without facing: frame %d, want 0", got)
+	}
+}
@@ -7,22 +7,42 @@ import (
 	"embed"
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
+	_ "image/png" // register the png decoder for image.Decode
+	"io/fs"
 	"path/filepath"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 //go:embed assets/textures/*.png
 var texturesFS embed.FS
 
-// TextureStrips represents an array of vertical strips of a texture image.
-type TextureStrips [TextureSize]*ebiten.Image
+// TextureStrips represents the vertical strips of a texture image, one per pixel column and frame row.
+// The strip of column x in frame row r is at index r*width + x.
+type TextureStrips []*ebiten.Image
 
 // Texture represents a texture with its source image and vertical strips.
+// Source is the region of the texture atlas holding the image, Strips are sub images of it.
+// Pixels holds the decoded pixels on the CPU, sampled by the software renderer.
+// Opaque holds, per frame row and texture column, the rows between the first and the last non transparent pixel,
+// relative to the top of the frame row.
+// Size is the width and height in pixels of one frame, a power of two. Textures are drawn the same size in the
+// world whatever their resolution, a 128 pixel texture simply has more detail than a 64 pixel one.
+// Frames is the number of frames side by side in the image, 1 for a plain texture.
+// A sprite sheet with 8 frames is a directional sprite, see directionalFrame.
+// Rows is the number of frame rows stacked in the image, the animation frames of a sprite.
 type Texture struct {
 	Source *ebiten.Image
 	Strips TextureStrips
+	Pixels *image.RGBA
+	Opaque []rowRange
+	Size   int
+	Frames int
+	Rows   int
 }
 
 // TextureMap maps texture IDs to their corresponding Texture.
@@ -48,39 +68,209 @@ const (
 	Chains           TextureID = 133
 	Light            TextureID = 134
 	WasdKeys         TextureID = 135
+	Poof             TextureID = 136
+	Teleporter       TextureID = 137
+	// Digits is the sheet of the digits 1 to 9, one per frame, drawn as the subscripts of the marks.
+	Digits TextureID = 138
 )
 
-// LoadTextures loads all textures defined in imageManifest.
-// Each file is decoded once into Source, and Strips are derived for raycasting.
+// LoadTextures loads all textures defined in imageManifest, see UploadTextures.
 func LoadTextures() (TextureMap, error) {
+	out, err := LoadTexturePixels()
+	if err != nil {
+		return nil, err
+	}
+	return UploadTextures(out)
+}
+
+// UploadTextures creates the ebiten images of textures decoded into Pixels.
+// All textures are packed into a single atlas image uploaded to the GPU,
+// so consecutive draws of walls and sprites share their source texture and can be batched.
+// Source is the region of each texture in the atlas and Strips are derived from it for raycasting.
+func UploadTextures(textures TextureMap) (TextureMap, error) {
+	out := make(TextureMap, len(textures))
+
+	atlas, err := newTextureAtlas(textures, TextureAtlasMaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("pack texture atlas: %w", err)
+	}
+	atlasImage := ebiten.NewImageFromImage(atlas.Pixels)
+
+	for id, texture := range textures {
+		img, ok := atlasImage.SubImage(atlas.Rects[id]).(*ebiten.Image)
+		if !ok || img == nil {
+			return nil, fmt.Errorf("texture %d is not in the atlas", id)
+		}
+
+		strips, errS := sliceIntoVerticalStrips(img, texture.Size)
+		if errS != nil {
+			return nil, fmt.Errorf("slice texture %d: %w", id, errS)
+		}
+
+		texture.Source = img
+		texture.Strips = strips
+		out[id] = texture
+	}
+
+	return out, nil
+}
+
+// LoadTexturePixels decodes all textures defined in imageManifest without creating ebiten images.
+// Only Pixels is set, it is enough for the software renderer and works without a window.
+func LoadTexturePixels() (TextureMap, error) {
 	out := make(TextureMap, len(imageManifest))
 
 	for id, filename := range imageManifest {
 		fullPath := filepath.ToSlash(filepath.Join(TextureFolder, filename))
 
-		f, err := texturesFS.Open(fullPath)
+		pixels, err := decodeTexturePixels(texturesFS, fullPath)
 		if err != nil {
-			return nil, fmt.Errorf("open %q: %w", fullPath, err)
+			return nil, err
 		}
 
-		img, _, err := ebitenutil.NewImageFromReader(f)
-		_ = f.Close()
+		texture, err := newTexture(pixels, textureFrames[id])
 		if err != nil {
-			return nil, fmt.Errorf("decode %q: %w", fullPath, err)
+			return nil, fmt.Errorf("%q: %w", fullPath, err)
 		}
+		out[id] = texture
+	}
 
-		strips, err := sliceIntoVerticalStrips(img)
-		if err != nil {
-			return nil, fmt.Errorf("slice %q: %w", fullPath, err)
-		}
+	return out, nil
+}
+
+// newTexture describes decoded pixels holding frames frames side by side (0 means 1).
+// frames are square with a power of two size, animation frames are stacked below each other.
+func newTexture(pixels *image.RGBA, frames int) (Texture, error) {
+	frames = max(frames, 1)
+	width, height := pixels.Rect.Dx(), pixels.Rect.Dy()
+
+	if width == 0 || width%frames != 0 {
+		return Texture{}, fmt.Errorf("width %d is not a multiple of %d frames", width, frames)
+	}
+	size := width / frames
+	if !isPowerOfTwo(size) {
+		return Texture{}, fmt.Errorf("frame size %d is not a power of two", size)
+	}
+	if height == 0 || height%size != 0 {
+		return Texture{}, fmt.Errorf("height %d is not a multiple of the frame size %d", height, size)
+	}
+
+	return Texture{
+		Source: nil,
+		Strips: nil,
+		Pixels: pixels,
+		Opaque: opaqueColumnRows(pixels, size),
+		Size:   size,
+		Frames: frames,
+		Rows:   height / size,
+	}, nil
+}
+
+// isPowerOfTwo returns true for 1, 2, 4, 8...
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// decodeTexturePixels decodes an image file into premultiplied RGBA pixels.
+func decodeTexturePixels(fsys fs.FS, fullPath string) (*image.RGBA, error) {
+	f, err := fsys.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %q: %w", fullPath, err)
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %q: %w", fullPath, err)
+	}
+
+	// always copy so the pixels start at (0,0) with a tight stride
+	bounds := src.Bounds()
+	pixels := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(pixels, pixels.Bounds(), src, bounds.Min, draw.Src)
+	return pixels, nil
+}
 
-		out[id] = Texture{
-			Source: img,
-			Strips: strips,
+// opaqueColumnRows returns, for each frame row of frameHeight pixels and each column,
+// the rows from the first to the last pixel with a non zero alpha, relative to the top of the frame row.
+// the range of column x in frame row r is at index r*width + x, fully transparent columns get an empty range.
+func opaqueColumnRows(pixels *image.RGBA, frameHeight int) []rowRange {
+	bounds := pixels.Bounds()
+	width := bounds.Dx()
+	frameRows := bounds.Dy() / frameHeight
+	out := make([]rowRange, frameRows*width)
+
+	for row := range frameRows {
+		for x := range width {
+			first, last := -1, -1
+			for y := range frameHeight {
+				if pixels.RGBAAt(x, row*frameHeight+y).A == 0 {
+					continue
+				}
+				if first < 0 {
+					first = y
+				}
+				last = y
+			}
+
+			if first >= 0 {
+				out[row*width+x] = rowRange{start: first, end: last + 1}
+			}
 		}
 	}
+	return out
+}
 
-	return out, nil
+// frameSize returns the size of one frame, TextureSize for a texture without pixels.
+func (t Texture) frameSize() int {
+	if t.Size <= 0 {
+		return TextureSize
+	}
+	return t.Size
+}
+
+// frameColumn returns the image column at u (0..1) inside the given frame.
+// frames out of range wrap around, u is clamped to the frame.
+func (t Texture) frameColumn(frame int, u float64) int {
+	frames := max(t.Frames, 1)
+	frame = ((frame % frames) + frames) % frames
+	size := t.frameSize()
+	return frame*size + clampInt(int(u*float64(size)), 0, size-1)
+}
+
+// frameRow wraps an animation row into the rows of the texture.
+func (t Texture) frameRow(row int) int {
+	rows := max(t.Rows, 1)
+	return ((row % rows) + rows) % rows
+}
+
+// frameTop returns the first image row of the animation row.
+func (t Texture) frameTop(row int) int {
+	return t.frameRow(row) * t.frameSize()
+}
+
+// strip returns the strip of image column x in the animation row, nil if the texture has no strips.
+func (t Texture) strip(row, x int) *ebiten.Image {
+	i := t.frameRow(row)*t.width() + x
+	if x < 0 || i >= len(t.Strips) {
+		return nil
+	}
+	return t.Strips[i]
+}
+
+// width returns the image width, all frames included.
+func (t Texture) width() int {
+	return max(t.Frames, 1) * t.frameSize()
+}
+
+// opaqueRows returns the rows of texture column x in the animation row that may contain visible pixels,
+// relative to the top of the frame row. without alpha information every row is returned.
+func (t Texture) opaqueRows(row, x int) rowRange {
+	i := t.frameRow(row)*t.width() + x
+	if x >= 0 && i < len(t.Opaque) {
+		return t.Opaque[i]
+	}
+	return rowRange{start: 0, end: t.frameSize()}
 }
 
 func LoadHUDImage(name string) (*ebiten.Image, error) {
@@ -98,36 +288,66 @@ func LoadHUDImage(name string) (*ebiten.Image, error) {
 	return img, nil
 }
 
-// sliceIntoVerticalStrips returns TextureSize images of width 1.
-// The source texture must be exactly TextureSize pixels wide.
-func sliceIntoVerticalStrips(src *ebiten.Image) (TextureStrips, error) {
+// sliceIntoVerticalStrips returns one image of width 1 and height size per source column and frame row.
+// The source texture width and height must be multiples of the frame size, sprite sheets hold several frames.
+// The source can be a sub image, of the texture atlas for instance.
+func sliceIntoVerticalStrips(src *ebiten.Image, size int) (TextureStrips, error) {
 	// get source image bounds
 	bounds := src.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
 
-	// enforce fixed texture width for raycasting
-	if width != TextureSize {
-		return TextureStrips{}, fmt.Errorf("expected texture width %d, got %d", TextureSize, width)
+	// enforce whole frames for raycasting
+	if size <= 0 || width == 0 || width%size != 0 {
+		return nil, fmt.Errorf("expected a texture width multiple of %d, got %d", size, width)
 	}
 
-	// height must be valid
-	if height <= 0 {
-		return TextureStrips{}, fmt.Errorf("invalid texture height %d", height)
+	// height must be valid, animated sprites stack their frames vertically
+	if height == 0 || height%size != 0 {
+		return nil, fmt.Errorf("expected a texture height multiple of %d, got %d", size, height)
 	}
 
-	// array of 1 pixel wide vertical texture strips
-	var strips TextureStrips
+	// 1 pixel wide vertical texture strips
+	rows := height / size
+	strips := make(TextureStrips, rows*width)
 
-	for x := range TextureSize {
-		// take a 1 pixel wide slice from the source texture
-		sub, ok := src.SubImage(image.Rect(x, 0, x+1, height)).(*ebiten.Image)
-		if !ok || sub == nil {
-			return TextureStrips{}, fmt.Errorf("failed to slice texture column x=%d", x)
-		}
+	for row := range rows {
+		top := bounds.Min.Y + row*size
+		for x := range width {
+			// take a 1 pixel wide slice of the frame row from the source texture
+			left := bounds.Min.X + x
+			sub, ok := src.SubImage(image.Rect(left, top, left+1, top+size)).(*ebiten.Image)
+			if !ok || sub == nil {
+				return nil, fmt.Errorf("failed to slice texture column x=%d row=%d", x, row)
+			}
 
-		// store the strip directly, subimage shares the same underlying texture
-		strips[x] = sub
+			// store the strip directly, subimage shares the same underlying texture
+			strips[row*width+x] = sub
+		}
 	}
 
 	return strips, nil
 }
+
+// drawTextureIcon draws the first frame of the texture in a size x size square at (x, y),
+// or a square of the fallback color when the texture is not loaded.
+func drawTextureIcon(screen *ebiten.Image, texture Texture, x, y, size float64, fallback color.Color) {
+	drawFadedTextureIcon(screen, texture, x, y, size, 0, fallback)
+}
+
+// drawFadedTextureIcon draws the icon like drawTextureIcon, see-through by fade from 0 (opaque) to 1 (invisible).
+func drawFadedTextureIcon(screen *ebiten.Image, texture Texture, x, y, size, fade float64, fallback color.Color) {
+	if texture.Source == nil {
+		vector.FillRect(screen, float32(x), float32(y), float32(size), float32(size), fallback, false)
+		return
+	}
+
+	frame := texture.frameSize()
+	b := texture.Source.Bounds()
+	icon, _ := texture.Source.SubImage(image.Rect(b.Min.X, b.Min.Y, b.Min.X+frame, b.Min.Y+frame)).(*ebiten.Image)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(size/float64(frame), size/float64(frame))
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleAlpha(float32(1 - fade))
+	screen.DrawImage(icon, op)
+}
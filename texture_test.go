@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestLoadTexturePixels_SpriteSheets(t *testing.T) {
+	textures, err := LoadTexturePixels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, id := range []TextureID{PlayerXCharacter, PlayerOCharacter} {
+		if frames := textures[id].Frames; frames != 8 {
+			t.Errorf("texture %d has %d frames, want 8", id, frames)
+		}
+	}
+	if frames := textures[WallBrick].Frames; frames != 1 {
+		t.Errorf("wall texture has %d frames, want 1", frames)
+	}
+}
+
+func TestLoadTexturePixels_AnimationRows(t *testing.T) {
+	textures, err := LoadTexturePixels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// every animation must fit in the rows of its texture
+	tests := []struct {
+		id   TextureID
+		anim *Animation
+	}{
+		{id: Light, anim: &AnimLanternFlicker},
+		{id: PlayerXCharacter, anim: &AnimWalk},
+		{id: PlayerOCharacter, anim: &AnimWalk},
+		{id: Poof, anim: &AnimPoof},
+	}
+
+	for _, tt := range tests {
+		rows := textures[tt.id].Rows
+		for _, f := range tt.anim.Frames {
+			if f.Row >= rows {
+				t.Errorf("texture %d has %d rows, the animation uses row %d", tt.id, rows, f.Row)
+			}
+		}
+	}
+	if rows := textures[WallBrick].Rows; rows != 1 {
+		t.Errorf("wall texture has %d rows, want 1", rows)
+	}
+}
+
+func TestTexture_FrameColumn(t *testing.T) {
+	tex := Texture{Source: nil, Strips: nil, Pixels: nil, Opaque: nil, Size: TextureSize, Frames: 8, Rows: 1}
+
+	tests := []struct {
+		frame int
+		u     float64
+		want  int
+	}{
+		{frame: 0, u: 0, want: 0},
+		{frame: 0, u: 1, want: TextureSize - 1},
+		{frame: 2, u: 0.5, want: 2*TextureSize + TextureSize/2},
+		{frame: 9, u: 0, want: TextureSize},
+		{frame: -1, u: 0, want: 7 * TextureSize},
+	}
+
+	for _, tt := range tests {
+		if got := tex.frameColumn(tt.frame, tt.u); got != tt.want {
+			t.Errorf("frameColumn(%d, %v) = %d, want %d", tt.frame, tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestTexture_OpaqueRowsPerFrameRow(t *testing.T) {
+	tex := Texture{
+		Source: nil,
+		Strips: nil,
+		Pixels: nil,
+		Opaque: make([]rowRange, 2*TextureSize),
+		Size:   TextureSize,
+		Frames: 1,
+		Rows:   2,
+	}
+	tex.Opaque[TextureSize+3] = rowRange{start: 10, end: 20}
+
+	if got := tex.opaqueRows(1, 3); got != (rowRange{start: 10, end: 20}) {
+		t.Errorf("opaqueRows(1, 3) = %+v, want rows 10..20", got)
+	}
+
+	// rows wrap around like frames
+	if got := tex.opaqueRows(3, 3); got != (rowRange{start: 10, end: 20}) {
+		t.Errorf("opaqueRows(3, 3) = %+v, want rows 10..20", got)
+	}
+	if got := tex.frameTop(3); got != TextureSize {
+		t.Errorf("frameTop(3) = %d, want %d", got, TextureSize)
+	}
+}
+
+func TestNewTexture(t *testing.T) {
+	tests := []struct {
+		name      string
+		w, h      int
+		frames    int
+		wantSize  int
+		wantRows  int
+		wantError bool
+	}{
+		{name: "Reference size", w: 64, h: 64, frames: 0, wantSize: 64, wantRows: 1},
+		{name: "High resolution", w: 256, h: 256, frames: 1, wantSize: 256, wantRows: 1},
+		{name: "Low resolution", w: 16, h: 16, frames: 1, wantSize: 16, wantRows: 1},
+		{name: "High resolution sheet", w: 1024, h: 640, frames: 8, wantSize: 128, wantRows: 5},
+		{name: "Animation rows", w: 32, h: 128, frames: 1, wantSize: 32, wantRows: 4},
+		{name: "Not a power of two", w: 96, h: 96, frames: 1, wantError: true},
+		{name: "Width not a multiple of frames", w: 100, h: 64, frames: 8, wantError: true},
+		{name: "Height not a multiple of the frame", w: 64, h: 100, frames: 1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tex, err := newTexture(image.NewRGBA(image.Rect(0, 0, tt.w, tt.h)), tt.frames)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tex.Size != tt.wantSize || tex.Rows != tt.wantRows {
+				t.Errorf("size %d rows %d, want size %d rows %d", tex.Size, tex.Rows, tt.wantSize, tt.wantRows)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "math"
+
+// TurnTransition is the camera moving from the view of the previous player to the view of the next one
+// during StateTurnTransition, the world is drawn from camera instead of the current player while it is active.
+// The position is interpolated in a straight line, through the walls, and the direction turns the shortest way.
+// The camera changes level of the dungeon halfway when the players stand on different levels.
+// The zero value is inactive, the camera is created by the first Start.
+type TurnTransition struct {
+	camera    *Player
+	active    bool
+	fromPos   Vec2
+	toPos     Vec2
+	fromDir   Vec2
+	toDir     Vec2
+	fromLevel int
+	toLevel   int
+}
+
+// Start activates the transition from the view of from to the view of to and places the camera at from.
+func (t *TurnTransition) Start(from, to *Player) {
+	if t.camera == nil {
+		t.camera = NewPlayer(0, 0, PlayerSymbolNone, "")
+	}
+
+	t.active = true
+	t.fromPos, t.fromDir = from.pos, from.dir
+	t.toPos, t.toDir = to.pos, to.dir
+	t.fromLevel, t.toLevel = from.level, to.level
+	t.Update(0)
+}
+
+// Stop deactivates the transition, the world is drawn from the current player again.
+func (t *TurnTransition) Stop() {
+	t.active = false
+}
+
+// Camera returns the transition camera, nil when the transition is inactive.
+func (t *TurnTransition) Camera() *Player {
+	if !t.active {
+		return nil
+	}
+	return t.camera
+}
+
+// Update moves the camera to the given progress of the transition, from 0 (previous player) to 1 (next player).
+func (t *TurnTransition) Update(progress float64) {
+	if !t.active {
+		return
+	}
+
+	s := smoothstep(math.Min(math.Max(progress, 0), 1))
+	t.camera.pos = t.fromPos.Add(t.toPos.Sub(t.fromPos).Scale(s))
+	t.camera.dir = lerpDirection(t.fromDir, t.toDir, s)
+
+	t.camera.level = t.fromLevel
+	if Two*s >= 1 {
+		t.camera.level = t.toLevel
+	}
+}
+
+// smoothstep eases t from 0 to 1, starting and ending slowly.
+func smoothstep(t float64) float64 {
+	return t * t * (Two*(1-t) + 1)
+}
+
+// lerpDirection turns the unit direction from toward to by the fraction t of the smallest angle between them.
+func lerpDirection(from, to Vec2, t float64) Vec2 {
+	start := math.Atan2(from.Y, from.X)
+	delta := math.Atan2(to.Y, to.X) - start
+
+	// the shortest way round
+	delta = math.Remainder(delta, Two*math.Pi)
+
+	angle := start + delta*t
+	return Vec2{X: math.Cos(angle), Y: math.Sin(angle)}
+}
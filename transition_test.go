@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLerpDirection_TurnsTheShortestWay(t *testing.T) {
+	// from just above east to just below east, the short way crosses east, not west
+	from := Vec2{X: 1, Y: 0}.Rotate(-0.2)
+	to := Vec2{X: 1, Y: 0}.Rotate(0.2)
+
+	mid := lerpDirection(from, to, 0.5)
+	if math.Abs(mid.X-1) > 1e-9 || math.Abs(mid.Y) > 1e-9 {
+		t.Errorf("lerpDirection(0.5) = %v, want east", mid)
+	}
+
+	end := lerpDirection(from, to, 1)
+	if math.Abs(end.X-to.X) > 1e-9 || math.Abs(end.Y-to.Y) > 1e-9 {
+		t.Errorf("lerpDirection(1) = %v, want %v", end, to)
+	}
+}
+
+func TestSmoothstep(t *testing.T) {
+	for _, tt := range []struct{ in, want float64 }{{0, 0}, {0.5, 0.5}, {1, 1}} {
+		if got := smoothstep(tt.in); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("smoothstep(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGame_TurnTransition(t *testing.T) {
+	pX := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+	pO := NewPlayer(9.5, 1.5, PlayerSymbolO, "O")
+	pO.dir = Vec2{X: 0, Y: 1}
+	g := &Game{worldMap: NewMap(), playerX: pX, playerO: pO, currentPlayer: pO, state: StatePlaying}
+
+	g.startTurnTransition(pX)
+	if g.state != StateTurnTransition || g.viewer().pos != pX.pos {
+		t.Fatalf("the transition should start from the previous player, camera at %v", g.viewer().pos)
+	}
+
+	g.stateTimer = TurnTransitionDuration/Two + DeltaTime
+	_ = g.updateTurnTransition()
+	if pos := g.viewer().pos; math.Abs(pos.X-5.5) > 1e-9 || pos.Y != 1.5 {
+		t.Errorf("halfway camera at %v, want (5.5,1.5)", pos)
+	}
+
+	for g.state == StateTurnTransition {
+		_ = g.updateTurnTransition()
+	}
+	if g.state != StatePlaying || g.viewer() != pO {
+		t.Errorf("state = %d, the next player should play from their own view", g.state)
+	}
+}
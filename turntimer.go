@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "math/rand/v2"
+
+// TurnTimeoutKind selects what happens when the current player runs out of time.
+type TurnTimeoutKind string
+
+const (
+	// TurnTimeoutSkip passes the turn to the other player.
+	TurnTimeoutSkip TurnTimeoutKind = "skip"
+	// TurnTimeoutRandom places the mark of the current player on a random empty cell.
+	TurnTimeoutRandom TurnTimeoutKind = "random"
+	// TurnTimeoutForfeit ends the round, won by the other player.
+	TurnTimeoutForfeit TurnTimeoutKind = "forfeit"
+)
+
+// isValidTurnTimeout returns true if k is one of the timeout kinds.
+func isValidTurnTimeout(k TurnTimeoutKind) bool {
+	return k == TurnTimeoutSkip || k == TurnTimeoutRandom || k == TurnTimeoutForfeit
+}
+
+// TurnTimer is the time left to the current player to place a mark.
+// It is part of the game state: the random placements only depend on Seed and the number of Timeouts,
+// so a game replayed from the same state times out the same way.
+// The zero value is stopped.
+type TurnTimer struct {
+	Remaining float64
+	Running   bool
+	Seed      uint64
+	Timeouts  uint64
+}
+
+// Start restarts the countdown with limit seconds, a limit of 0 stops the timer.
+func (t *TurnTimer) Start(limit float64) {
+	t.Remaining = limit
+	t.Running = limit > 0
+}
+
+// Stop stops the countdown.
+func (t *TurnTimer) Stop() {
+	t.Running = false
+}
+
+// Tick counts dt seconds down and returns true once, when the time runs out.
+func (t *TurnTimer) Tick(dt float64) bool {
+	if !t.Running {
+		return false
+	}
+
+	t.Remaining -= dt
+	if t.Remaining > 0 {
+		return false
+	}
+
+	t.Remaining = 0
+	t.Running = false
+	t.Timeouts++
+	return true
+}
+
+// RandomMove returns one of the legal moves picked from the seed and the number of timeouts,
+// ok is false when there is none.
+func (t *TurnTimer) RandomMove(moves []Move) (Move, bool) {
+	if len(moves) == 0 {
+		return Move{}, false
+	}
+
+	//nolint:gosec // a game move, reproducible on purpose
+	rng := rand.New(rand.NewPCG(t.Seed, t.Timeouts))
+	return moves[rng.IntN(len(moves))], true
+}
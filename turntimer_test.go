@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "testing"
+
+func TestTurnTimer_TickExpiresOnce(t *testing.T) {
+	var timer TurnTimer
+	if timer.Tick(1) {
+		t.Fatal("a stopped timer never expires")
+	}
+
+	timer.Start(1)
+	if timer.Tick(0.5) {
+		t.Fatal("expired with time left")
+	}
+	if !timer.Tick(0.5) {
+		t.Fatal("did not expire when the time ran out")
+	}
+	if timer.Tick(1) || timer.Running || timer.Timeouts != 1 {
+		t.Errorf("timer = %+v, want it stopped after a single timeout", timer)
+	}
+
+	timer.Start(0)
+	if timer.Running {
+		t.Error("a limit of 0 disables the timer")
+	}
+}
+
+func TestTurnTimer_RandomMoveIsReproducible(t *testing.T) {
+	b := Cube{{
+		{PlayerSymbolX, PlayerSymbolO, PlayerSymbolX},
+		{PlayerSymbolNone, PlayerSymbolO, PlayerSymbolNone},
+		{PlayerSymbolO, PlayerSymbolX, PlayerSymbolNone},
+	}}
+	moves := ClassicMode{}.LegalMoves(&b, PlayerSymbolX)
+
+	for timeouts := range uint64(20) {
+		a := TurnTimer{Seed: 42, Timeouts: timeouts}
+		m, ok := a.RandomMove(moves)
+		if !ok || b.At(m.Cell) != PlayerSymbolNone {
+			t.Fatalf("RandomMove() = %v, %v, want a move on an empty cell", m, ok)
+		}
+
+		replay := TurnTimer{Seed: 42, Timeouts: timeouts}
+		if again, _ := replay.RandomMove(moves); again != m {
+			t.Errorf("the same timer state picked %v then %v", m, again)
+		}
+	}
+
+	var timer TurnTimer
+	if _, ok := timer.RandomMove(nil); ok {
+		t.Error("there is no move to pick without legal moves")
+	}
+}
+
+func TestGame_HandleTurnTimeout(t *testing.T) {
+	newGame := func(timeout TurnTimeoutKind) *Game {
+		pX := NewPlayer(1.5, 1.5, PlayerSymbolX, "X")
+		pO := NewPlayer(9.5, 1.5, PlayerSymbolO, "O")
+		g := &Game{worldMap: NewMap(), playerX: pX, playerO: pO, currentPlayer: pX, state: StatePlaying}
+		g.settings.TurnTimeout = timeout
+		g.settings.TurnTimeLimit = 10
+		g.timer.Start(g.settings.TurnTimeLimit)
+		return g
+	}
+	expire := func(g *Game) {
+		for g.state == StatePlaying {
+			if err := g.updatePlaying(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	g := newGame(TurnTimeoutSkip)
+	expire(g)
+	if g.currentPlayer != g.playerO || g.state != StateTurnTransition || g.board != (Cube{}) {
+		t.Errorf("skip: state = %d, board = %v, want the turn passed to O", g.state, g.board)
+	}
+
+	g = newGame(TurnTimeoutRandom)
+	expire(g)
+	if g.currentPlayer != g.playerO {
+		t.Fatal("random: the turn should pass to O")
+	}
+	marks := 0
+	for _, row := range g.board[0] {
+		for _, symbol := range row {
+			if symbol == PlayerSymbolX {
+				marks++
+			}
+		}
+	}
+	if marks != 1 {
+		t.Errorf("random: board = %v, want a single X", g.board)
+	}
+
+	g = newGame(TurnTimeoutForfeit)
+	expire(g)
+	if g.state != StateGameOver || g.winner != g.playerO || g.playerO.score != 1 {
+		t.Errorf("forfeit: state = %d, want O to win the round", g.state)
+	}
+}
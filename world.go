@@ -4,7 +4,11 @@
 package main
 
 import (
+	"image"
+	"image/color"
 	"math"
+	"runtime"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -12,8 +16,17 @@ import (
 
 type World struct {
 	zBuffer  []float64
+	wallRows []rowRange
 	cameraX  []float64
 	fovScale float64
+	stats    *RenderStats
+
+	ceilingColor color.RGBA
+	floorColor   color.RGBA
+
+	// renderer selects the gpu (draw call per column) or the software (pixel buffer) path
+	renderer RendererKind
+	software *SoftwareRenderer
 }
 
 // Draw renders the world view.
@@ -24,10 +37,28 @@ func (w *World) Draw(screen *ebiten.Image, g *Game) {
 		return
 	}
 
-	drawCeiling(screen)
-	drawFloor(screen)
+	start := time.Now()
+
+	// stats is nil unless the debug overlay is enabled
+	w.stats = g.debug.frameStats()
+
+	if w.renderer == RendererSoftware {
+		w.drawSoftware(screen, g)
+	} else {
+		w.drawGPU(screen, g)
+	}
+
+	if w.stats != nil {
+		w.stats.renderTime = time.Since(start)
+	}
+}
 
-	p := g.currentPlayer
+// drawGPU draws the view with one DrawImage call per wall and sprite column.
+func (w *World) drawGPU(screen *ebiten.Image, g *Game) {
+	drawCeiling(screen, w.ceilingColor)
+	drawFloor(screen, w.floorColor)
+
+	p := g.viewer()
 	if p == nil {
 		return
 	}
@@ -42,12 +73,51 @@ func (w *World) Draw(screen *ebiten.Image, g *Game) {
 	w.drawSprites(screen, g, p)
 }
 
+// drawSoftware computes the view into a pixel buffer on all cores and draws it with a single upload.
+func (w *World) drawSoftware(screen *ebiten.Image, g *Game) {
+	p := g.viewer()
+	if p == nil {
+		drawCeiling(screen, w.ceilingColor)
+		drawFloor(screen, w.floorColor)
+		return
+	}
+
+	if w.software == nil {
+		w.software = NewSoftwareRenderer(WindowSizeX, WindowSizeY, runtime.GOMAXPROCS(0))
+	}
+
+	w.software.Render(RenderView{
+		Map:      g.levelMap(p.level),
+		Textures: g.assets.Textures,
+		Pos:      p.pos,
+		Dir:      p.dir,
+		FOVScale: w.fovScale,
+		Sprites:  worldSprites(g, p),
+		Ceiling:  w.ceilingColor,
+		Floor:    w.floorColor,
+		// the HUD panel is translucent, sprites must not show through it
+		ClipBottom: HudTopLeftYPixels,
+	})
+	w.software.Draw(screen)
+
+	if w.stats != nil {
+		w.ensureRenderBuffers()
+		for x := range WindowSizeX {
+			if hit := w.software.Hit(x); hit.hit {
+				w.recordRayStats(w.stats, p, x, hit)
+			}
+		}
+		w.stats.visibleSprites = w.software.VisibleSprites()
+	}
+}
+
 // ensureRenderBuffers allocates and fills per frame constant buffers.
-// zBuffer stores the distance per screen column.
+// zBuffer stores the distance per screen column and wallRows the rows covered by its wall slice.
 // cameraX stores camera x coordinates per screen column (between -1 and 1).
 func (w *World) ensureRenderBuffers() {
 	if w.zBuffer == nil || len(w.zBuffer) != WindowSizeX {
 		w.zBuffer = make([]float64, WindowSizeX)
+		w.wallRows = make([]rowRange, WindowSizeX)
 	}
 
 	if w.cameraX == nil || len(w.cameraX) != WindowSizeX {
@@ -69,20 +139,38 @@ func (w *World) raycastColumnsAndDrawWalls(screen *ebiten.Image, g *Game, p *Pla
 		hit, ok := w.castRayForScreenColumn(g, p, x)
 		if !ok {
 			w.zBuffer[x] = math.Inf(1)
+			w.wallRows[x] = rowRange{start: 0, end: 0}
 			continue
 		}
 
 		w.zBuffer[x] = hit.distance
 
-		strip, ok := w.resolveTextureStripFromHit(g, hit)
+		if w.stats != nil {
+			w.recordRayStats(w.stats, p, x, hit)
+		}
+
+		strip, ok := w.resolveTextureStripFromHit(g, p, hit)
 		if !ok {
 			continue
 		}
 
 		lineH := w.wallSliceHeightOnScreen(hit.distance)
 		drawStart := w.wallSliceTopY(lineH)
+		w.wallRows[x] = pixelRows(drawStart, drawStart+lineH, WindowSizeY)
+
+		w.drawTexturedWallSlice(screen, strip, x, drawStart, lineH, wallShade(hit))
+	}
+}
 
-		w.drawTexturedWallSlice(screen, strip, x, drawStart, lineH, hit.distance)
+// recordRayStats stores the centre column hit and the ray fan hit points for the debug overlay.
+func (w *World) recordRayStats(stats *RenderStats, p *Player, x int, hit RayHit) {
+	if x == WindowSizeXDiv2 {
+		stats.centreHit = hit
+	}
+	if x%DebugRayFanStep == 0 {
+		// the perpendicular distance scales the unnormalized ray direction to the hit point
+		rayDir := GetRayDirection(p.dir, w.fovScale, w.cameraX[x])
+		stats.rayEnds = append(stats.rayEnds, p.pos.Add(rayDir.Scale(hit.distance)))
 	}
 }
 
@@ -98,7 +186,8 @@ func (w *World) castRayForScreenColumn(g *Game, p *Player, x int) (RayHit, bool)
 
 	rayDir := GetRayDirection(p.dir, w.fovScale, w.cameraX[x])
 
-	hit := CastRay(p.pos, rayDir, g.worldMap, MaxRayIter)
+	m := g.levelMap(p.level)
+	hit := CastRay(p.pos, rayDir, m, m.MaxRayIterations())
 	if !hit.hit || math.IsInf(hit.distance, 1) || hit.distance <= 0 {
 		return RayHit{}, false
 	}
@@ -106,20 +195,15 @@ func (w *World) castRayForScreenColumn(g *Game, p *Player, x int) (RayHit, bool)
 	return hit, true
 }
 
-// resolveTextureStripFromHit converts the map hit into a texture strip image.
-// it uses the hit cell to read the tile id, maps it to a texture id, then uses wallX to pick the strip.
-func (w *World) resolveTextureStripFromHit(g *Game, hit RayHit) (*ebiten.Image, bool) {
+// resolveTextureStripFromHit converts the hit on the map of the level of p into a texture strip image.
+// it uses the hit cell and face to get the texture id, then uses wallX to pick the strip.
+func (w *World) resolveTextureStripFromHit(g *Game, p *Player, hit RayHit) (*ebiten.Image, bool) {
 	if g == nil || g.assets == nil {
 		return nil, false
 	}
 
-	// get the texture id
-	tileID, outOfBounds := g.worldMap.GetTileID(hit.cellX, hit.cellY)
-	if outOfBounds {
-		return nil, false
-	}
-
-	textureID, ok := tileID.TextureID()
+	// get the texture id, faces can override the tile texture
+	textureID, ok := g.levelMap(p.level).WallTexture(hit.cellX, hit.cellY, hit.face)
 	if !ok {
 		return nil, false
 	}
@@ -129,22 +213,18 @@ func (w *World) resolveTextureStripFromHit(g *Game, hit RayHit) (*ebiten.Image,
 		return nil, false
 	}
 
-	stripIndex := w.textureStripIndexFromWallX(hit.wallX, len(texture.Strips))
-	return texture.Strips[stripIndex], true
+	// walls always use the first frame
+	strip := texture.strip(0, texture.frameColumn(0, wallTextureU(hit)))
+	return strip, strip != nil
 }
 
-// textureStripIndexFromWallX converts wallX (0..1) into a strip index for the texture.
-// it clamps the index to avoid out of range due to floating point rounding.
-func (w *World) textureStripIndexFromWallX(wallX float64, stripCount int) int {
-	if stripCount <= 1 {
-		return 0
+// wallTextureU returns the horizontal texture coordinate (0..1) of the hit.
+// it mirrors wallX on the faces where it grows to the left, so textures are never flipped.
+func wallTextureU(hit RayHit) float64 {
+	if hit.face.Mirrored() {
+		return 1 - hit.wallX
 	}
-
-	// stripIndex is the x coordinate inside the texture (converted to a strip index)
-	stripIndex := int(wallX * float64(stripCount))
-
-	// clamp stripIndex to valid range
-	return clampInt(stripIndex, 0, stripCount-1)
+	return hit.wallX
 }
 
 // wallSliceHeightOnScreen returns the wall slice height in pixels for a given hit distance.
@@ -164,14 +244,14 @@ func (w *World) wallSliceTopY(lineH float64) float64 {
 }
 
 // drawTexturedWallSlice draws one vertical textured strip on screen.
-// it scales the strip to the projected wall height, applies distance shading, and draws it at column x.
+// it scales the strip to the projected wall height, applies the shade, and draws it at column x.
 func (w *World) drawTexturedWallSlice(
 	screen *ebiten.Image,
 	textureStrip *ebiten.Image,
 	x int,
 	drawStart float64,
 	lineH float64,
-	distance float64,
+	shade float32,
 ) {
 	if screen == nil || textureStrip == nil {
 		return
@@ -185,21 +265,29 @@ func (w *World) drawTexturedWallSlice(
 
 	op := &ebiten.DrawImageOptions{}
 
-	// scale the strip to match the wall height on screen
-	scaleY := lineH / float64(TextureSize)
+	// scale the strip to match the wall height on screen, whatever the texture resolution
+	scaleY := lineH / float64(textureStrip.Bounds().Dy())
 	op.GeoM.Scale(1, scaleY)
 
-	// put shading based on the distance
-	colorScale := w.distanceShadeScale(distance)
-	op.ColorScale.Scale(colorScale, colorScale, colorScale, 1)
+	op.ColorScale.Scale(shade, shade, shade, 1)
 
 	op.GeoM.Translate(float64(x), drawStart)
 	screen.DrawImage(textureStrip, op)
 }
 
-// distanceShadeScale returns a grayscale multiplier for distance shading.
+// wallShade returns the grayscale multiplier of a wall hit.
+// walls facing north or south (side 1) are darker so corners stay readable.
+func wallShade(hit RayHit) float32 {
+	shade := distanceShade(hit.distance)
+	if hit.side == 1 {
+		shade *= WallSideShade
+	}
+	return shade
+}
+
+// distanceShade returns a grayscale multiplier for distance shading.
 // farther walls get darker.
-func (w *World) distanceShadeScale(distance float64) float32 {
+func distanceShade(distance float64) float32 {
 	if distance <= 0 || math.IsInf(distance, 1) || math.IsNaN(distance) {
 		return 1
 	}
@@ -207,6 +295,16 @@ func (w *World) distanceShadeScale(distance float64) float32 {
 	return float32(1.0) / (1.0 + float32(distance)/20.0)
 }
 
+// spriteShade returns the multiplier of a projected sprite: the distance shade brightened by its glow.
+func spriteShade(sp spriteProjection) float32 {
+	return distanceShade(sp.depth) * float32(1+sp.glow)
+}
+
+// spriteOpacity returns the alpha multiplier of a projected sprite, 1 unless it fades.
+func spriteOpacity(sp spriteProjection) float32 {
+	return float32(1 - min(max(sp.fade, 0), 1))
+}
+
 // drawSprites renders all world sprites.
 // it uses the z-buffer to clip sprites behind walls and sorts sprites back-to-front.
 func (w *World) drawSprites(screen *ebiten.Image, g *Game, p *Player) {
@@ -217,66 +315,99 @@ func (w *World) drawSprites(screen *ebiten.Image, g *Game, p *Player) {
 		return
 	}
 
-	// build camera plane used by ray direction: rayDir = dir + plane * cameraX
-	plane := Vec2{
-		X: -p.dir.Y * w.fovScale,
-		Y: p.dir.X * w.fovScale,
+	plane := cameraPlane(p.dir, w.fovScale)
+	sortedSprites := SortSpritesByDistance(worldSprites(g, p), p.pos)
+
+	visible := 0
+	for _, s := range sortedSprites {
+		if w.drawSingleSprite(screen, g, p, plane, s) {
+			visible++
+		}
 	}
 
-	// collect visible sprites with distance for sorting
-	allSprites := make([]*Sprite, 0, len(g.sprites)+1)
-	allSprites = append(allSprites, g.sprites...)
+	if w.stats != nil {
+		w.stats.visibleSprites = visible
+	}
+}
 
-	// add other player as a sprite
-	var other *Player
-	if g.currentPlayer == g.playerX {
-		other = g.playerO
-	} else {
-		other = g.playerX
+// worldSprites returns the sprites seen by p: the decorations and the players in front of it, except p itself,
+// standing on the level of p.
+func worldSprites(g *Game, p *Player) []*Sprite {
+	allSprites := make([]*Sprite, 0, len(g.sprites)+Two)
+	for _, s := range g.sprites {
+		if s.Level == p.level {
+			allSprites = append(allSprites, s)
+		}
 	}
 
-	if other != nil {
+	// add the other players as sprites, unless they are behind the camera plane
+	for _, other := range []*Player{g.playerX, g.playerO} {
+		if other == nil || other == p || other.level != p.level || other.pos.Sub(p.pos).Dot(p.dir) <= 0 {
+			continue
+		}
 		allSprites = append(allSprites, &Sprite{
 			Position:  other.pos,
 			TextureID: other.characterTextureID,
 			Scale:     1.0,
 			Z:         0.0,
 			Hidden:    false,
+			Facing:    other.dir,
+			Anim:      other.anim,
 		})
 	}
+	return allSprites
+}
 
-	sortedSprites := SortSpritesByDistance(allSprites, p.pos)
-
-	for _, s := range sortedSprites {
-		w.drawSingleSprite(screen, g, p, plane, s)
+// cameraPlane returns the camera plane used by ray direction: rayDir = dir + plane * cameraX.
+func cameraPlane(dir Vec2, fovScale float64) Vec2 {
+	return Vec2{
+		X: -dir.Y * fovScale,
+		Y: dir.X * fovScale,
 	}
 }
 
-// drawSingleSprite projects one sprite into the screen and draws it column by column.
-func (w *World) drawSingleSprite(screen *ebiten.Image, g *Game, p *Player, plane Vec2, s *Sprite) {
-	texture := g.assets.Textures[s.TextureID]
-	if len(texture.Strips) == 0 {
-		return
-	}
+// spriteProjection is a sprite projected on a screen of a given size.
+// depth is the distance along the view direction, used for the z-buffer test and shading.
+// screenX is the column of the sprite center and size its width and height in pixels.
+// startX and endX are the visible columns (inclusive), startY is the top row (can be off screen).
+// frame is the sprite sheet frame to draw and texSize its size in pixels, both set by the renderer.
+// row is the animation frame row, glow and fade are the ones of the sprite.
+type spriteProjection struct {
+	textureID TextureID
+	frame     int
+	texSize   int
+	row       int
+	depth     float64
+	screenX   int
+	size      int
+	startX    int
+	endX      int
+	startY    int
+	glow      float64
+	fade      float64
+}
 
+// projectSprite projects a sprite seen from pos looking along dir on a width x height screen.
+// it returns ok=false if the sprite is behind the camera or off screen.
+func projectSprite(pos, dir, plane Vec2, s *Sprite, width, height int) (spriteProjection, bool) {
 	// sprite position relative to player
-	spriteX := s.Position.X - p.pos.X
-	spriteY := s.Position.Y - p.pos.Y
+	spriteX := s.Position.X - pos.X
+	spriteY := s.Position.Y - pos.Y
 
 	// inverse determinant for camera transform
-	det := plane.X*p.dir.Y - p.dir.X*plane.Y
+	det := plane.X*dir.Y - dir.X*plane.Y
 	if det == 0 {
-		return
+		return spriteProjection{}, false
 	}
 	invDet := 1.0 / det
 
 	// transform sprite into camera space
-	transformX := invDet * (p.dir.Y*spriteX - p.dir.X*spriteY)
+	transformX := invDet * (dir.Y*spriteX - dir.X*spriteY)
 	transformY := invDet * (-plane.Y*spriteX + plane.X*spriteY)
 
 	// transformY is depth (in front of camera must be > 0)
 	if transformY <= 0 || math.IsInf(transformY, 1) || math.IsNaN(transformY) {
-		return
+		return spriteProjection{}, false
 	}
 
 	// sprite scale guard
@@ -286,73 +417,227 @@ func (w *World) drawSingleSprite(screen *ebiten.Image, g *Game, p *Player, plane
 	}
 
 	// screen x of the sprite center
-	spriteScreenX := int(float64(WindowSizeXDiv2) * (1.0 + transformX/transformY))
+	spriteScreenX := int(float64(width/Two) * (1.0 + transformX/transformY))
 
-	// projected sprite size (classic)
-	spriteHeight := int((float64(WindowSizeY) / transformY) * scale)
-	if spriteHeight <= 0 {
-		return
+	// projected sprite size (classic), sprites are square
+	spriteSize := int((float64(height) / transformY) * scale)
+	if spriteSize <= 0 {
+		return spriteProjection{}, false
 	}
 
-	spriteWidth := spriteHeight
-
 	// vertical placement
 	// z shifts the sprite up in world units, scaled by depth into pixels
-	zOffsetPx := int((s.Z / transformY) * float64(WindowSizeYDiv2))
-	drawStartY := -spriteHeight/2 + WindowSizeYDiv2 - zOffsetPx
+	zOffsetPx := int((s.Z / transformY) * float64(height/Two))
+	drawStartY := -spriteSize/Two + height/Two - zOffsetPx
+
+	// horizontal placement, clipped to screen bounds
+	drawStartX := max(-spriteSize/Two+spriteScreenX, 0)
+	drawEndX := min(spriteSize/Two+spriteScreenX, width-1)
+	if drawStartX > drawEndX {
+		return spriteProjection{}, false
+	}
+
+	return spriteProjection{
+		textureID: s.TextureID,
+		frame:     0,
+		texSize:   TextureSize,
+		row:       s.Anim.Row(),
+		depth:     transformY,
+		screenX:   spriteScreenX,
+		size:      spriteSize,
+		startX:    drawStartX,
+		endX:      drawEndX,
+		startY:    drawStartY,
+		glow:      s.Glow,
+		fade:      s.Fade,
+	}, true
+}
+
+// subscriptProjection returns the projection of the digit drawn at the bottom right of a sprite projected as sp,
+// at the same depth so walls hide both alike. ok is false when the subscript is not a digit from 1 to 9
+// or the digit is off screen.
+func subscriptProjection(sp spriteProjection, subscript, width int) (spriteProjection, bool) {
+	if subscript < 1 || subscript > SubscriptMax {
+		return spriteProjection{}, false
+	}
+
+	size := int(float64(sp.size) * SubscriptScale)
+	if size <= 0 {
+		return spriteProjection{}, false
+	}
+	screenX := sp.screenX + int(float64(sp.size)*SubscriptOffsetX)
+	startX := max(screenX-size/Two, 0)
+	endX := min(screenX+size/Two, width-1)
+	if startX > endX {
+		return spriteProjection{}, false
+	}
+
+	return spriteProjection{
+		textureID: Digits,
+		frame:     subscript - 1,
+		texSize:   TextureSize,
+		row:       0,
+		depth:     sp.depth,
+		screenX:   screenX,
+		size:      size,
+		startX:    startX,
+		endX:      endX,
+		startY:    sp.startY + int(float64(sp.size)*SubscriptBottom) - size,
+		glow:      sp.glow,
+		fade:      sp.fade,
+	}, true
+}
 
-	// horizontal placement
-	drawStartX := -spriteWidth/Two + spriteScreenX
-	drawEndX := spriteWidth/Two + spriteScreenX
+// textureU maps the screen column x into [0..1] inside the projected sprite.
+func (sp spriteProjection) textureU(x int) float64 {
+	return float64(x-(sp.screenX-sp.size/Two)) / float64(sp.size)
+}
+
+// texelHeight returns the height in pixels of one texture row, a whole frame covers the projected size.
+func (sp spriteProjection) texelHeight() float64 {
+	return float64(sp.size) / float64(max(sp.texSize, 1))
+}
+
+// rowRange is the half-open range [start, end) of screen or texture rows.
+type rowRange struct {
+	start int
+	end   int
+}
+
+// empty returns true if the range has no rows.
+func (r rowRange) empty() bool {
+	return r.end <= r.start
+}
 
-	// clip to screen bounds
-	if drawStartX < 0 {
-		drawStartX = 0
+// pixelRows returns the screen rows whose pixel center is in [top, bottom), clipped to [0, height).
+func pixelRows(top, bottom float64, height int) rowRange {
+	return rowRange{
+		start: max(int(math.Ceil(top-0.5)), 0),
+		end:   min(int(math.Ceil(bottom-0.5)), height),
 	}
-	if drawEndX >= WindowSizeX {
-		drawEndX = WindowSizeX - 1
+}
+
+// spriteColumnRows returns the rows of a sprite column to draw, at most two ranges (above and below a wall).
+// opaque are the texture rows with visible pixels in this column, only those are drawn.
+// rows are clipped to [0, clipBottom) and, when the wall of the column is closer, to the rows outside the wall slice.
+func spriteColumnRows(
+	sp spriteProjection,
+	opaque rowRange,
+	wallDistance float64,
+	wall rowRange,
+	clipBottom int,
+) [2]rowRange {
+	top := float64(sp.startY)
+	texelH := sp.texelHeight()
+	rows := pixelRows(top+float64(opaque.start)*texelH, top+float64(opaque.end)*texelH, clipBottom)
+
+	if sp.depth < wallDistance {
+		return [2]rowRange{rows, {start: 0, end: 0}}
 	}
 
-	// precompute shading from depth
-	shade := w.distanceShadeScale(transformY)
+	return [2]rowRange{
+		{start: rows.start, end: min(rows.end, wall.start)},
+		{start: max(rows.start, wall.end), end: rows.end},
+	}
+}
 
-	stripCount := len(texture.Strips)
-	if stripCount <= 0 {
-		return
+// texelRows returns the texture rows sampled by the screen rows, for a texture drawn from top with texelH pixel rows.
+func texelRows(rows rowRange, top, texelH float64, texH int) rowRange {
+	return rowRange{
+		start: clampInt(int((float64(rows.start)+0.5-top)/texelH), 0, texH),
+		end:   clampInt(int((float64(rows.end)-0.5-top)/texelH)+1, 0, texH),
 	}
+}
 
-	// draw one screen column at a time, selecting the matching texture strip
-	for x := drawStartX; x <= drawEndX; x++ {
-		// z-buffer test: if wall is closer, skip this sprite column
-		if transformY >= w.zBuffer[x] {
-			continue
-		}
+// drawSingleSprite projects one sprite into the screen and draws it column by column.
+// it returns true if the sprite was in front of the camera and on screen.
+func (w *World) drawSingleSprite(screen *ebiten.Image, g *Game, p *Player, plane Vec2, s *Sprite) bool {
+	texture := g.assets.Textures[s.TextureID]
+	if len(texture.Strips) == 0 {
+		return false
+	}
+
+	sp, ok := projectSprite(p.pos, p.dir, plane, s, WindowSizeX, WindowSizeY)
+	if !ok {
+		return false
+	}
+	sp.frame = directionalFrame(s.Facing, p.pos.Sub(s.Position), texture.Frames)
+	sp.texSize = texture.frameSize()
+	w.drawProjectedSprite(screen, texture, sp)
+
+	// the subscript is drawn over the sprite
+	digits := g.assets.Textures[Digits]
+	if sub, okS := subscriptProjection(sp, s.Subscript, WindowSizeX); okS && len(digits.Strips) > 0 {
+		sub.texSize = digits.frameSize()
+		w.drawProjectedSprite(screen, digits, sub)
+	}
 
-		// map current screen x into [0..1] inside sprite
-		u := (float64(x - (spriteScreenX - spriteWidth/2))) / float64(spriteWidth)
+	return true
+}
+
+// drawProjectedSprite draws the texture of a projected sprite column by column.
+func (w *World) drawProjectedSprite(screen *ebiten.Image, texture Texture, sp spriteProjection) {
+	// precompute shading from depth
+	shade := spriteShade(sp)
+
+	// draw one screen column at a time, selecting the matching texture strip
+	for x := sp.startX; x <= sp.endX; x++ {
+		u := sp.textureU(x)
 		if u < 0 || u > 1 {
 			continue
 		}
 
-		stripIndex := int(u * float64(stripCount))
-		stripIndex = clampInt(stripIndex, 0, stripCount-1)
-
-		strip := texture.Strips[stripIndex]
+		stripIndex := texture.frameColumn(sp.frame, u)
+		strip := texture.strip(sp.row, stripIndex)
 		if strip == nil {
 			continue
 		}
 
-		// scale strip vertically to match projected sprite height
-		op := &ebiten.DrawImageOptions{}
-		scaleY := float64(spriteHeight) / float64(TextureSize)
-		op.GeoM.Scale(1, scaleY)
+		// only the visible rows: on screen, above the HUD, not behind the wall and not transparent
+		opaque := texture.opaqueRows(sp.row, stripIndex)
+		for _, rows := range spriteColumnRows(sp, opaque, w.zBuffer[x], w.wallRows[x], HudTopLeftYPixels) {
+			if !rows.empty() {
+				w.drawSpriteStripRows(screen, strip, sp, x, rows, shade)
+			}
+		}
+	}
+}
 
-		// apply distance shading
-		op.ColorScale.Scale(shade, shade, shade, 1)
+// drawSpriteStripRows draws the screen rows of a sprite strip at column x.
+// the destination is a sub image of the rows, so partially covered texels are clipped to the exact pixels.
+func (w *World) drawSpriteStripRows(
+	screen *ebiten.Image,
+	strip *ebiten.Image,
+	sp spriteProjection,
+	x int,
+	rows rowRange,
+	shade float32,
+) {
+	top := float64(sp.startY)
+	texelH := sp.texelHeight()
 
-		op.GeoM.Translate(float64(x), float64(drawStartY))
-		screen.DrawImage(strip, op)
+	bounds := strip.Bounds()
+	texels := texelRows(rows, top, texelH, bounds.Dy())
+	if texels.empty() {
+		return
 	}
+
+	src, okS := strip.SubImage(image.Rect(bounds.Min.X, texels.start, bounds.Max.X, texels.end)).(*ebiten.Image)
+	dst, okD := screen.SubImage(image.Rect(x, rows.start, x+1, rows.end)).(*ebiten.Image)
+	if !okS || !okD {
+		return
+	}
+
+	// scale the texel rows to the projected sprite height
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(1, texelH)
+
+	// apply distance shading, and the fade on the premultiplied colors
+	opacity := spriteOpacity(sp)
+	op.ColorScale.Scale(shade*opacity, shade*opacity, shade*opacity, opacity)
+
+	op.GeoM.Translate(float64(x), top+float64(texels.start)*texelH)
+	dst.DrawImage(src, op)
 }
 
 // clampInt clamps an int value in the inclusive range [lo, hi].
@@ -367,15 +652,15 @@ func clampInt(v, lo, hi int) int {
 }
 
 // drawCeiling draws the ceiling color rectangle.
-func drawCeiling(screen *ebiten.Image) {
+func drawCeiling(screen *ebiten.Image, col color.RGBA) {
 	if screen == nil {
 		return
 	}
-	vector.FillRect(screen, float32(0), float32(0), float32(WindowSizeX), float32(WindowSizeYDiv2), ColorCeiling, false)
+	vector.FillRect(screen, float32(0), float32(0), float32(WindowSizeX), float32(WindowSizeYDiv2), col, false)
 }
 
 // drawFloor draws the floor color rectangle.
-func drawFloor(screen *ebiten.Image) {
+func drawFloor(screen *ebiten.Image, col color.RGBA) {
 	if screen == nil {
 		return
 	}
@@ -385,7 +670,7 @@ func drawFloor(screen *ebiten.Image) {
 		float32(WindowSizeYDiv2),
 		float32(WindowSizeX),
 		float32(WindowSizeYDiv2),
-		ColorFloor,
+		col,
 		false,
 	)
 }
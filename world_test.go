@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Elwan Mayencourt, Masami Morimura
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestPixelRows(t *testing.T) {
+	tests := []struct {
+		name        string
+		top, bottom float64
+		want        rowRange
+	}{
+		{name: "Pixel centers", top: 2.4, bottom: 5.5, want: rowRange{start: 2, end: 5}},
+		{name: "Clipped above", top: -30, bottom: 3, want: rowRange{start: 0, end: 3}},
+		{name: "Clipped below", top: 8, bottom: 40, want: rowRange{start: 8, end: 10}},
+		{name: "Off screen", top: 20, bottom: 40, want: rowRange{start: 20, end: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pixelRows(tt.top, tt.bottom, 10)
+			if got != tt.want {
+				t.Errorf("pixelRows(%v, %v) = %+v, want %+v", tt.top, tt.bottom, got, tt.want)
+			}
+		})
+	}
+
+	if !pixelRows(20, 40, 10).empty() {
+		t.Error("off screen rows should be empty")
+	}
+}
+
+func TestSpriteColumnRows(t *testing.T) {
+	// a 64px sprite from row 10, one texel per pixel, opaque on texture rows 4..60
+	sp := spriteProjection{
+		textureID: Light, depth: 5, screenX: 0, size: TextureSize, texSize: TextureSize, startX: 0, endX: 0, startY: 10,
+	}
+	opaque := rowRange{start: 4, end: 60}
+	wall := rowRange{start: 30, end: 50}
+
+	// the wall is farther, only the transparent rows and the clip row limit the sprite
+	got := spriteColumnRows(sp, opaque, 8, wall, 100)
+	if got[0] != (rowRange{start: 14, end: 70}) || !got[1].empty() {
+		t.Errorf("wall behind: %+v", got)
+	}
+
+	// the wall is closer, the sprite is only drawn above and below the wall slice
+	got = spriteColumnRows(sp, opaque, 3, wall, 100)
+	if got[0] != (rowRange{start: 14, end: 30}) || got[1] != (rowRange{start: 50, end: 70}) {
+		t.Errorf("wall in front: %+v", got)
+	}
+
+	// rows from the clip row are never drawn
+	got = spriteColumnRows(sp, opaque, 3, wall, 40)
+	if got[0] != (rowRange{start: 14, end: 30}) || !got[1].empty() {
+		t.Errorf("clipped: %+v", got)
+	}
+
+	// a full height wall in front hides the whole column
+	got = spriteColumnRows(sp, opaque, 3, rowRange{start: 0, end: 100}, 100)
+	if !got[0].empty() || !got[1].empty() {
+		t.Errorf("hidden: %+v", got)
+	}
+}
+
+func TestTexelRows(t *testing.T) {
+	// texels of 2.5 pixels starting at row 10
+	got := texelRows(rowRange{start: 15, end: 20}, 10, 2.5, TextureSize)
+	if got != (rowRange{start: 2, end: 4}) {
+		t.Errorf("texelRows = %+v, want 2..4", got)
+	}
+}
+
+func TestOpaqueColumnRows(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 12))
+	img.SetRGBA(0, 1, color.RGBA{R: 255, A: 255})
+	img.SetRGBA(0, 3, color.RGBA{A: 10})
+	img.SetRGBA(2, 5, color.RGBA{B: 255, A: 255})
+
+	// second frame row, ranges are relative to its top
+	img.SetRGBA(1, 8, color.RGBA{G: 255, A: 255})
+
+	got := opaqueColumnRows(img, 6)
+	want := []rowRange{
+		{start: 1, end: 4}, {start: 0, end: 0}, {start: 5, end: 6},
+		{start: 0, end: 0}, {start: 2, end: 3}, {start: 0, end: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d ranges, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d column %d = %+v, want %+v", i/3, i%3, got[i], want[i])
+		}
+	}
+}
+
+func TestWorldSprites_CullsOtherPlayerBehind(t *testing.T) {
+	pX := NewPlayer(5, 5, PlayerSymbolX, "X")
+	pX.dir = Vec2{X: 1, Y: 0}
+	pO := NewPlayer(7, 5, PlayerSymbolO, "O")
+	g := &Game{playerX: pX, playerO: pO, sprites: nil}
+
+	if got := worldSprites(g, pX); len(got) != 1 || got[0].TextureID != pO.characterTextureID {
+		t.Fatalf("other player in front: got %d sprites", len(got))
+	}
+
+	pX.dir = pX.dir.Rotate(math.Pi)
+	if got := worldSprites(g, pX); len(got) != 0 {
+		t.Errorf("other player behind the camera: got %d sprites, want 0", len(got))
+	}
+}
+
+func TestWorldSprites_FlyoverCameraSeesBothPlayers(t *testing.T) {
+	pX := NewPlayer(5, 5, PlayerSymbolX, "X")
+	pO := NewPlayer(7, 5, PlayerSymbolO, "O")
+	g := &Game{playerX: pX, playerO: pO, sprites: nil}
+
+	camera := NewPlayer(3, 5, PlayerSymbolNone, "")
+	camera.dir = Vec2{X: 1, Y: 0}
+	if got := worldSprites(g, camera); len(got) != 2 {
+		t.Errorf("flyover camera: got %d sprites, want both players", len(got))
+	}
+}
+
+func TestSpriteShade_Glow(t *testing.T) {
+	sp := spriteProjection{depth: 4}
+	base := spriteShade(sp)
+
+	sp.glow = 1
+	if got := spriteShade(sp); math.Abs(float64(got-2*base)) > 1e-6 {
+		t.Errorf("spriteShade with a glow of 1 = %v, want twice %v", got, base)
+	}
+}
+
+func TestSpriteOpacity_Fade(t *testing.T) {
+	for fade, want := range map[float64]float32{0: 1, 0.25: 0.75, 2: 0} {
+		if got := spriteOpacity(spriteProjection{fade: fade}); got != want {
+			t.Errorf("spriteOpacity(fade %v) = %v, want %v", fade, got, want)
+		}
+	}
+}
+
+func TestSubscriptProjection(t *testing.T) {
+	sp := spriteProjection{depth: 3, screenX: 100, size: 200, startX: 0, endX: 199, startY: 50, fade: 0.5}
+	if _, ok := subscriptProjection(sp, 0, WindowSizeX); ok {
+		t.Error("a sprite without subscript has no digit")
+	}
+
+	sub, ok := subscriptProjection(sp, 3, WindowSizeX)
+	if !ok || sub.textureID != Digits || sub.frame != 2 {
+		t.Fatalf("subscriptProjection() = %+v, want the frame of the digit 3", sub)
+	}
+	// a small digit at the bottom right of the sprite, behind the same walls
+	if sub.size >= sp.size || sub.screenX <= sp.screenX || sub.startY+sub.size > sp.startY+sp.size {
+		t.Errorf("digit %+v is not at the bottom right of the sprite %+v", sub, sp)
+	}
+	if sub.depth != sp.depth || sub.fade != sp.fade {
+		t.Errorf("digit depth %v fade %v, want the ones of the sprite", sub.depth, sub.fade)
+	}
+}